@@ -0,0 +1,226 @@
+package pt
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+/*
+TB is the same as [Test], but the test body accepts [testing.TB].
+Such test is run as a usual test by [Parallel], [PackageParallel] and others,
+and it can be run as a sub-benchmark by [Benchmark], so tests and benchmarks are declared once.
+
+	var sumTests = []testing.InternalTest{
+		pt.TB("should sum positive values", func(tb testing.TB) {
+			if sum(2, 3) != 5 {
+				tb.Fail()
+			}
+		}),
+	}
+
+	func TestSum(t *testing.T) {
+		pt.PackageParallel(t, sumTests...)
+	}
+
+	func BenchmarkSum(b *testing.B) {
+		pt.Benchmark(b, sumTests...)
+	}
+*/
+func TB(name string, test func(tb testing.TB), opts ...Option) testing.InternalTest {
+	if test == nil {
+		panic("argument test func(tb testing.TB) can not be nil")
+	}
+	n := &node{
+		name: name,
+		kind: KindTest,
+		f:    func(t *testing.T) { test(t) },
+		tb:   test,
+	}
+	for _, opt := range opts {
+		opt(n)
+	}
+	return register(n)
+}
+
+/*
+Benchmark runs provided tests as sub-benchmarks of b.
+Every [Group] and [Serial] is a sub-benchmark containing its tests,
+every test built by [TB], [EachTB] or [TableTB] is a sub-benchmark which runs the test body b.N times
+(or with [testing.B.RunParallel] if the test or its group has [RunParallel] option).
+
+	func BenchmarkUser(b *testing.B) {
+		pt.Benchmark(b, userTests...)
+	}
+
+Tests which require [*testing.T] (built by [Test] or arbitrary [testing.InternalTest]) and pending tests are skipped.
+Groups which declare hooks (e.g. [BeforeEach]) or [Provide] are skipped as a whole,
+because hooks and fixtures require [*testing.T] as well and tests of such groups can't run without them.
+Other options (e.g. [Limit], [Timeout], [Retry]) and pt environment variables are not applied to benchmarks,
+use -bench and -benchtime flags of go test to select benchmarks.
+*/
+func Benchmark(b *testing.B, tests ...testing.InternalTest) {
+	if b == nil {
+		panic("argument b *testing.B can not be nil")
+	}
+	benchmark(b, tests, false)
+}
+
+// RunParallel returns an option which makes [Benchmark] run the test or each test of the group
+// with [testing.B.RunParallel]. It doesn't affect tests run by [Parallel] and others.
+// Note that the test body is called concurrently, so it must not call [testing.TB.FailNow] and the like.
+func RunParallel() Option {
+	return func(target *node) {
+		target.runParallel = true
+	}
+}
+
+// benchmark runs tests as sub-benchmarks of b, parallel is true if the enclosing group has [RunParallel] option.
+func benchmark(b *testing.B, tests []testing.InternalTest, parallel bool) {
+	if hasSetup(tests) {
+		b.Skip("group has hooks or pt.Provide which require *testing.T, move tests out of it to benchmark")
+	}
+	for _, test := range subtests(tests) {
+		n := lookup(test)
+		b.Run(test.Name, func(b *testing.B) {
+			benchmarkTest(b, n, parallel)
+		})
+	}
+}
+
+// hasSetup returns true if tests contain hooks or [Provide] directives.
+func hasSetup(tests []testing.InternalTest) bool {
+	for _, test := range tests {
+		if n := lookup(test); n != nil && (n.kind == KindHook || n.kind == KindProvide) {
+			return true
+		}
+	}
+	return false
+}
+
+// benchmarkTest runs test n (nil if test is not built by pt) as b.
+func benchmarkTest(b *testing.B, n *node, parallel bool) {
+	if n == nil {
+		b.Skip("test requires *testing.T, build it with pt.TB to benchmark")
+	}
+	if n.pending {
+		b.Skip("pending")
+	}
+	parallel = parallel || n.runParallel
+	switch n.kind {
+	case KindGroup, KindSerial:
+		benchmark(b, n.children, parallel)
+	case KindRepeat:
+		benchmarkTest(b, lookup(n.children[0]), parallel)
+	default:
+		if n.tb == nil {
+			b.Skip("test requires *testing.T, build it with pt.TB to benchmark")
+		}
+		if parallel {
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					n.tb(b)
+				}
+			})
+			return
+		}
+		for i := 0; i < b.N; i++ {
+			n.tb(b)
+		}
+	}
+}
+
+/*
+Seed adds cases of [Each], [Table], [EachTB] and [TableTB] found in provided tests (including nested groups)
+to the seed corpus of the fuzz target f, so table-driven tests and the fuzz target share cases.
+A case of struct type is added as separate values of its fields in declaration order,
+other cases are added as a single value. Types of values must be supported by [testing.F.Add].
+
+	func FuzzSum(f *testing.F) {
+		pt.Seed(f, sumTests...) // sumCase{a, b, expected int}
+		f.Fuzz(func(t *testing.T, a, b, _ int) {
+			if sum(a, b) != sum(b, a) {
+				t.Fail()
+			}
+		})
+	}
+*/
+func Seed(f *testing.F, tests ...testing.InternalTest) {
+	if f == nil {
+		panic("argument f *testing.F can not be nil")
+	}
+	for _, c := range caseValues(tests) {
+		f.Add(seedArgs(c)...)
+	}
+}
+
+// caseValues returns values of cases of tests and nested groups in declaration order.
+func caseValues(tests []testing.InternalTest) []any {
+	var values []any
+	for _, test := range tests {
+		n := lookup(test)
+		if n == nil {
+			continue
+		}
+		switch n.kind {
+		case KindCases:
+			values = append(values, n.cases...)
+		case KindGroup, KindSerial, KindRepeat:
+			values = append(values, caseValues(n.children)...)
+		}
+	}
+	return values
+}
+
+// seedArgs returns arguments of [testing.F.Add] for the case c.
+// Named types are converted to their underlying types, because [testing.F.Add] accepts only predeclared types.
+func seedArgs(c any) []any {
+	value := reflect.ValueOf(c)
+	if value.Kind() != reflect.Struct {
+		return []any{seedArg(c, value)}
+	}
+	args := make([]any, 0, value.NumField())
+	for i := 0; i < value.NumField(); i++ {
+		args = append(args, seedArg(c, value.Field(i)))
+	}
+	return args
+}
+
+// seedArg converts value to a predeclared type, fields are read without Interface(), so unexported fields are supported.
+func seedArg(c any, value reflect.Value) any {
+	switch value.Kind() {
+	case reflect.Bool:
+		return value.Bool()
+	case reflect.Int:
+		return int(value.Int())
+	case reflect.Int8:
+		return int8(value.Int())
+	case reflect.Int16:
+		return int16(value.Int())
+	case reflect.Int32:
+		return int32(value.Int())
+	case reflect.Int64:
+		return value.Int()
+	case reflect.Uint:
+		return uint(value.Uint())
+	case reflect.Uint8:
+		return uint8(value.Uint())
+	case reflect.Uint16:
+		return uint16(value.Uint())
+	case reflect.Uint32:
+		return uint32(value.Uint())
+	case reflect.Uint64:
+		return value.Uint()
+	case reflect.Float32:
+		return float32(value.Float())
+	case reflect.Float64:
+		return value.Float()
+	case reflect.String:
+		return value.String()
+	case reflect.Slice:
+		if value.Type().Elem().Kind() == reflect.Uint8 {
+			return append([]byte(nil), value.Bytes()...)
+		}
+	}
+	panic(fmt.Sprintf("case %+v can't be a seed: type %s is not supported by testing.F.Add", c, value.Type()))
+}
@@ -0,0 +1,80 @@
+package pt_test
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/maratori/pt"
+)
+
+func TestTB(t *testing.T) {
+	t.Parallel()
+	t.Run("should panic on nil test", func(t *testing.T) {
+		t.Parallel()
+		defer assertPanic(t, "argument test func(tb testing.TB) can not be nil")
+		pt.TB("", nil)
+	})
+	t.Run("should panic on nil test of EachTB", func(t *testing.T) {
+		t.Parallel()
+		defer assertPanic(t, "argument test func(tb testing.TB, c C) can not be nil")
+		pt.EachTB[int](nil, nil, nil)
+	})
+	t.Run("should panic on nil test of TableTB", func(t *testing.T) {
+		t.Parallel()
+		defer assertPanic(t, "argument test func(tb testing.TB, c C) can not be nil")
+		pt.TableTB[int](nil, nil)
+	})
+	t.Run("should run as test", func(t *testing.T) {
+		t.Parallel()
+		var events eventLog
+		t.Run("internal", func(it *testing.T) {
+			pt.Parallel(it,
+				pt.TB("test", func(tb testing.TB) { events.add(tb.Name()) }),
+				pt.EachTB([]int{1}, nil, func(tb testing.TB, c int) { events.add(tb.Name()) }),
+			)
+		})
+		actual := events.get()
+		sort.Strings(actual)
+		assertEvents(t, actual, t.Name()+"/internal/0", t.Name()+"/internal/test")
+	})
+	t.Run("should panic on nil B", func(t *testing.T) {
+		t.Parallel()
+		defer assertPanic(t, "argument b *testing.B can not be nil")
+		pt.Benchmark(nil)
+	})
+	t.Run("should panic on nil F", func(t *testing.T) {
+		t.Parallel()
+		defer assertPanic(t, "argument f *testing.F can not be nil")
+		pt.Seed(nil)
+	})
+	t.Run("should run benchmarks", func(t *testing.T) {
+		t.Parallel()
+		output, err := runTestdata(t, "bench", "GOFLAGS=-run=^$ -bench=. -benchtime=3x")
+		if err != nil {
+			t.Fatalf("go test failed: %s\n%s", err, output)
+		}
+		assertContains(t, output,
+			"BenchmarkSum/sum/case ",
+			"BenchmarkSum/sum/case#01 ",
+			"BenchmarkSum/sum/parallel ",
+			"BenchmarkSum/table ",
+			"test requires *testing.T, build it with pt.TB to benchmark\n--- SKIP: BenchmarkSum/sum/requires_T",
+			"--- SKIP: BenchmarkSum/sum/pending",
+			"group has hooks or pt.Provide which require *testing.T, move tests out of it to benchmark\n--- SKIP: BenchmarkSum/with_hooks",
+		)
+		assertNotContains(t, output, "BenchmarkSum/with_hooks/requires_hook")
+	})
+	t.Run("should seed fuzz target", func(t *testing.T) {
+		t.Parallel()
+		output, err := runTestdata(t, "bench", "GOFLAGS=-run=FuzzSum")
+		if err != nil {
+			t.Fatalf("go test failed: %s\n%s", err, output)
+		}
+		assertContains(t, output,
+			"seed 1 2 3\n",
+			"seed -4 4 0\n",
+			"--- PASS: FuzzSum/seed#1 ",
+		)
+		assertNotContains(t, output, "FuzzSum/seed#2")
+	})
+}
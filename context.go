@@ -0,0 +1,98 @@
+package pt
+
+import (
+	"context"
+	"flag"
+	"sync"
+	"testing"
+)
+
+/*
+TestCtx is the same as [Test], but the test body gets the context of the test (see [Context]) as the first argument.
+
+	pt.TestCtx("should create user", func(ctx context.Context, t *testing.T) {
+		user, err := client.CreateUser(ctx, "John")
+		...
+	}, pt.Timeout(5*time.Second))
+
+The context is derived from the context of the enclosing group, so it carries values set by [SetValue] in hooks.
+The context is cancelled when
+
+  - the test is finished (after AfterEach hooks);
+  - the test exceeds its [Timeout] or the timeout inherited from the group;
+  - another test run by pt fails and go test is run with -failfast flag.
+*/
+func TestCtx(name string, test func(ctx context.Context, t *testing.T), opts ...Option) testing.InternalTest {
+	if test == nil {
+		panic("argument test func(ctx context.Context, t *testing.T) can not be nil")
+	}
+	return Test(name, func(t *testing.T) {
+		test(Context(t), t)
+	}, opts...)
+}
+
+/*
+SetValue adds the value to the context of t (see [Context]).
+Being called in [BeforeAll], it makes the value available to all tests of the group (including nested groups),
+being called in [BeforeEach], it makes the value available to the test only.
+
+	pt.Group("user",
+		pt.BeforeAll(func(t *testing.T) {
+			pt.SetValue(t, tenantKey{}, createTenant(t))
+		}),
+		pt.TestCtx("should be created", func(ctx context.Context, t *testing.T) {
+			tenant := ctx.Value(tenantKey{}).(string)
+			...
+		}),
+	)
+
+Contexts returned by [Context] before the call don't contain the value.
+*/
+func SetValue(t *testing.T, key any, value any) {
+	if t == nil {
+		panic("argument t *testing.T can not be nil")
+	}
+	if key == nil {
+		panic("argument key can not be nil")
+	}
+	contexts.Store(t, context.WithValue(Context(t), key, value))
+}
+
+// parentContext returns the context of the group t belongs to.
+// The context of a top level test is cancelled on the first failure with -failfast flag of go test.
+func parentContext(t *testing.T) context.Context {
+	if s := scopeOf(t); s != nil {
+		return Context(s.t)
+	}
+	return rootContext()
+}
+
+// failFastContext is the root of contexts of all tests, it is cancelled on the first failure with -failfast flag.
+var failFastContext struct { //nolint:gochecknoglobals // shared by all tests in package
+	once   sync.Once
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// failFast cancels contexts of all tests if t is failed and go test is run with -failfast flag.
+func failFast(t *testing.T) {
+	if !t.Failed() || !failFastEnabled() {
+		return
+	}
+	rootContext()
+	failFastContext.cancel()
+}
+
+// failFastEnabled returns true if go test is run with -failfast flag.
+func failFastEnabled() bool {
+	f := flag.Lookup("test.failfast")
+	return f != nil && f.Value.String() == "true"
+}
+
+// rootContext returns the root of contexts of all tests.
+func rootContext() context.Context {
+	failFastContext.once.Do(func() {
+		failFastContext.ctx, failFastContext.cancel = context.WithCancel(context.Background())
+	})
+	return failFastContext.ctx
+}
@@ -0,0 +1,139 @@
+package pt_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/maratori/pt"
+)
+
+type contextKey string
+
+func TestTestCtx(t *testing.T) {
+	t.Parallel()
+	t.Run("should panic on nil test", func(t *testing.T) {
+		t.Parallel()
+		defer assertPanic(t, "argument test func(ctx context.Context, t *testing.T) can not be nil")
+		pt.TestCtx("", nil)
+	})
+	t.Run("should pass context of the test", func(t *testing.T) {
+		t.Parallel()
+		var ctx context.Context
+		t.Run("internal", func(it *testing.T) {
+			pt.Parallel(it, pt.TestCtx("", func(c context.Context, t *testing.T) {
+				ctx = c
+				if c != pt.Context(t) {
+					t.Error("context is not the context of the test")
+				}
+				if c.Err() != nil {
+					t.Error("context is cancelled during test")
+				}
+			}))
+		})
+		if ctx.Err() == nil {
+			t.Error("context is not cancelled")
+		}
+	})
+	t.Run("should derive context from group", func(t *testing.T) {
+		t.Parallel()
+		var groupCtx context.Context
+		var groupValue, testValue any
+		t.Run("internal", func(it *testing.T) {
+			pt.Parallel(it,
+				pt.Group("group",
+					pt.BeforeAll(func(t *testing.T) {
+						pt.SetValue(t, contextKey("group"), "group value")
+						groupCtx = pt.Context(t)
+					}),
+					pt.Group("nested",
+						pt.BeforeEach(func(t *testing.T) {
+							pt.SetValue(t, contextKey("test"), "test value")
+						}),
+						pt.TestCtx("test", func(ctx context.Context, t *testing.T) {
+							groupValue = ctx.Value(contextKey("group"))
+							testValue = ctx.Value(contextKey("test"))
+							if groupCtx.Err() != nil {
+								t.Error("context of the group is cancelled during test")
+							}
+						}),
+					),
+				),
+			)
+		})
+		if groupValue != "group value" {
+			t.Errorf("unexpected value of the group %v", groupValue)
+		}
+		if testValue != "test value" {
+			t.Errorf("unexpected value of the test %v", testValue)
+		}
+		if groupCtx.Err() == nil {
+			t.Error("context of the group is not cancelled")
+		}
+	})
+	t.Run("should not share values of BeforeEach", func(t *testing.T) {
+		t.Parallel()
+		var values []any
+		var events eventLog
+		t.Run("internal", func(it *testing.T) {
+			pt.Parallel(it,
+				pt.BeforeEach(func(t *testing.T) {
+					if pt.Context(t).Value(contextKey("test")) != nil {
+						events.add("value of another test")
+					}
+					pt.SetValue(t, contextKey("test"), t.Name())
+				}),
+				pt.TestCtx("a", func(ctx context.Context, t *testing.T) { values = append(values, ctx.Value(contextKey("test"))) }),
+				pt.TestCtx("b", func(ctx context.Context, t *testing.T) {}),
+			)
+		})
+		assertEvents(t, events.get())
+		if len(values) != 1 || values[0] != t.Name()+"/internal/a" {
+			t.Errorf("unexpected values %v", values)
+		}
+	})
+	t.Run("should cancel context on group timeout", func(t *testing.T) {
+		t.Parallel()
+		output, err := runTestdata(t, "timeout")
+		if err == nil {
+			t.Fatalf("go test succeeded:\n%s", output)
+		}
+		assertContains(t, output, "--- FAIL: TestTimeout/group/slow_ctx ")
+	})
+	t.Run("should cancel context on fail-fast", func(t *testing.T) {
+		t.Parallel()
+		output, err := runTestdata(t, "failfast", "GOFLAGS=-failfast -parallel=4")
+		if err == nil {
+			t.Fatalf("go test succeeded:\n%s", output)
+		}
+		assertContains(t, output, "context is cancelled\n--- FAIL: TestFailFast ")
+	})
+	t.Run("should not cancel context without fail-fast", func(t *testing.T) {
+		t.Parallel()
+		output, err := runTestdata(t, "failfast", "GOFLAGS=-parallel=4")
+		if err == nil {
+			t.Fatalf("go test succeeded:\n%s", output)
+		}
+		assertNotContains(t, output, "context is cancelled")
+	})
+}
+
+func TestSetValue(t *testing.T) {
+	t.Parallel()
+	t.Run("should panic on nil T", func(t *testing.T) {
+		t.Parallel()
+		defer assertPanic(t, "argument t *testing.T can not be nil")
+		pt.SetValue(nil, contextKey("key"), nil)
+	})
+	t.Run("should panic on nil key", func(t *testing.T) {
+		t.Parallel()
+		defer assertPanic(t, "argument key can not be nil")
+		pt.SetValue(t, nil, nil)
+	})
+	t.Run("should set value", func(t *testing.T) {
+		t.Parallel()
+		pt.SetValue(t, contextKey("key"), "value")
+		if value := pt.Context(t).Value(contextKey("key")); value != "value" {
+			t.Errorf("unexpected value %v", value)
+		}
+	})
+}
@@ -0,0 +1,137 @@
+package pt
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+)
+
+/*
+Each is a constructor of table-driven tests.
+It creates a [Test] for every case, tests run in parallel unless the enclosing group is [Serial].
+The name of a test is returned by the name function, if it is nil, the index of the case is used.
+When a test fails, the index, the name and the value of its case are logged.
+
+It is designed to be an argument of [Group], [Serial], [Parallel], [Sequential] and [PackageParallel].
+Tests are added directly to the enclosing group, so Each can be used along with other tests and hooks.
+Options can't be applied to Each by [With], apply them to the enclosing group instead.
+
+	pt.Group("should be sum of two values",
+		pt.Each([]sumCase{{0, 0, 0}, {0, 1, 1}, {5, 6, 11}},
+			func(c sumCase) string { return fmt.Sprintf("%d+%d = %d", c.a, c.b, c.expected) },
+			func(t *testing.T, c sumCase) {
+				if sum(c.a, c.b) != c.expected {
+					t.Fail()
+				}
+			},
+		),
+	)
+*/
+func Each[C any](cases []C, name func(c C) string, test func(t *testing.T, c C)) testing.InternalTest {
+	if test == nil {
+		panic("argument test func(t *testing.T, c C) can not be nil")
+	}
+	return newCases(eachNames(cases, name), cases, func(index int, name string, c C) testing.InternalTest {
+		return Test(name, func(t *testing.T) {
+			logCaseOnFailure(t, index, name, c)
+			test(t, c)
+		})
+	})
+}
+
+// Table is the same as [Each], but cases are passed as a map from name to case.
+// Tests are started in order of sorted names.
+func Table[C any](cases map[string]C, test func(t *testing.T, c C)) testing.InternalTest {
+	if test == nil {
+		panic("argument test func(t *testing.T, c C) can not be nil")
+	}
+	names, values := tableCases(cases)
+	return newCases(names, values, func(index int, name string, c C) testing.InternalTest {
+		return Test(name, func(t *testing.T) {
+			logCaseOnFailure(t, index, name, c)
+			test(t, c)
+		})
+	})
+}
+
+// EachTB is the same as [Each], but tests are built by [TB], so they can be run by [Benchmark] as well.
+// Cases can be used as seeds of a fuzz target with [Seed].
+func EachTB[C any](cases []C, name func(c C) string, test func(tb testing.TB, c C)) testing.InternalTest {
+	if test == nil {
+		panic("argument test func(tb testing.TB, c C) can not be nil")
+	}
+	return newCases(eachNames(cases, name), cases, func(index int, name string, c C) testing.InternalTest {
+		return TB(name, func(tb testing.TB) {
+			logCaseOnFailure(tb, index, name, c)
+			test(tb, c)
+		})
+	})
+}
+
+// TableTB is the same as [Table], but tests are built by [TB], so they can be run by [Benchmark] as well.
+// Cases can be used as seeds of a fuzz target with [Seed].
+func TableTB[C any](cases map[string]C, test func(tb testing.TB, c C)) testing.InternalTest {
+	if test == nil {
+		panic("argument test func(tb testing.TB, c C) can not be nil")
+	}
+	names, values := tableCases(cases)
+	return newCases(names, values, func(index int, name string, c C) testing.InternalTest {
+		return TB(name, func(tb testing.TB) {
+			logCaseOnFailure(tb, index, name, c)
+			test(tb, c)
+		})
+	})
+}
+
+// eachNames returns names of cases built by the name function or indexes of cases if it is nil.
+func eachNames[C any](cases []C, name func(c C) string) []string {
+	names := make([]string, 0, len(cases))
+	for i, c := range cases {
+		if name != nil {
+			names = append(names, name(c))
+		} else {
+			names = append(names, fmt.Sprint(i))
+		}
+	}
+	return names
+}
+
+// tableCases returns sorted names and corresponding cases.
+func tableCases[C any](cases map[string]C) ([]string, []C) {
+	names := make([]string, 0, len(cases))
+	for name := range cases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	values := make([]C, 0, len(cases))
+	for _, name := range names {
+		values = append(values, cases[name])
+	}
+	return names, values
+}
+
+// newCases builds [KindCases] node, the function test builds a test for every case.
+func newCases[C any](names []string, cases []C, test func(index int, name string, c C) testing.InternalTest) testing.InternalTest {
+	tests := make([]testing.InternalTest, 0, len(cases))
+	values := make([]any, 0, len(cases))
+	for i, c := range cases {
+		tests = append(tests, test(i, names[i], c))
+		values = append(values, c)
+	}
+	return register(&node{
+		kind:     KindCases,
+		children: tests,
+		cases:    values,
+	})
+}
+
+func logCaseOnFailure(tb testing.TB, index int, name string, c any) {
+	if _, ok := tb.(*testing.B); ok {
+		return // benchmark calls the test b.N times, cleanup would be added on every call
+	}
+	tb.Cleanup(func() {
+		if tb.Failed() {
+			tb.Logf("case #%d %q: %+v", index, name, c)
+		}
+	})
+}
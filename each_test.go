@@ -0,0 +1,126 @@
+package pt_test
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/maratori/pt"
+)
+
+func TestEach(t *testing.T) {
+	t.Parallel()
+	t.Run("should panic on nil test", func(t *testing.T) {
+		t.Parallel()
+		defer assertPanic(t, "argument test func(t *testing.T, c C) can not be nil")
+		pt.Each([]int{1}, nil, nil)
+	})
+	t.Run("should panic on options", func(t *testing.T) {
+		t.Parallel()
+		defer assertPanic(t, "options can not be applied to Each and Table, apply them to the enclosing group")
+		pt.With(pt.Each([]int{1}, nil, func(*testing.T, int) {}), pt.Limit(1))
+	})
+	t.Run("should not panic without cases", func(t *testing.T) {
+		t.Parallel()
+		pt.Parallel(t, pt.Each(nil, nil, func(*testing.T, int) {}))
+	})
+	t.Run("should run all cases", func(t *testing.T) {
+		t.Parallel()
+		var events eventLog
+		t.Run("internal", func(it *testing.T) {
+			pt.Parallel(it,
+				pt.Each([]int{1, 2, 3},
+					func(c int) string { return fmt.Sprintf("case %d", c) },
+					func(t *testing.T, c int) { events.add(fmt.Sprintf("%s=%d", t.Name(), c)) },
+				),
+			)
+		})
+		actual := events.get()
+		sort.Strings(actual)
+		prefix := t.Name() + "/internal/case_"
+		assertEvents(t, actual, prefix+"1=1", prefix+"2=2", prefix+"3=3")
+	})
+	t.Run("should use index as name", func(t *testing.T) {
+		t.Parallel()
+		var events eventLog
+		t.Run("internal", func(it *testing.T) {
+			pt.Parallel(it,
+				pt.Each([]string{"a", "b"}, nil, func(t *testing.T, c string) {
+					events.add(fmt.Sprintf("%s=%s", t.Name(), c))
+				}),
+			)
+		})
+		actual := events.get()
+		sort.Strings(actual)
+		prefix := t.Name() + "/internal/"
+		assertEvents(t, actual, prefix+"0=a", prefix+"1=b")
+	})
+	t.Run("should add cases to enclosing group", func(t *testing.T) {
+		t.Parallel()
+		var events eventLog
+		t.Run("internal", func(it *testing.T) {
+			pt.Parallel(it,
+				pt.Group("group",
+					pt.BeforeEach(events.hook("before")),
+					pt.Each([]int{1}, nil, func(t *testing.T, _ int) { events.add(t.Name()) }),
+				),
+			)
+		})
+		assertEvents(t, events.get(), "before", t.Name()+"/internal/group/0")
+	})
+	t.Run("should log failed case", func(t *testing.T) {
+		t.Parallel()
+		output, err := runTestdata(t, "each")
+		if err == nil {
+			t.Fatalf("go test succeeded:\n%s", output)
+		}
+		assertContains(t, output,
+			"--- FAIL: TestEach/sum#01 ",
+			`case #1 "sum": {a:2 b:2 expected:5}`,
+			"--- PASS: TestEach/sum ",
+		)
+		assertNotContains(t, output, `case #0 "sum"`)
+	})
+}
+
+func TestTable(t *testing.T) {
+	t.Parallel()
+	t.Run("should panic on nil test", func(t *testing.T) {
+		t.Parallel()
+		defer assertPanic(t, "argument test func(t *testing.T, c C) can not be nil")
+		pt.Table(map[string]int{}, nil)
+	})
+	t.Run("should panic on options", func(t *testing.T) {
+		t.Parallel()
+		defer assertPanic(t, "options can not be applied to Each and Table, apply them to the enclosing group")
+		pt.With(pt.Table(map[string]int{"a": 1}, func(*testing.T, int) {}), pt.Tags("slow"))
+	})
+	t.Run("should run all cases", func(t *testing.T) {
+		t.Parallel()
+		var events eventLog
+		t.Run("internal", func(it *testing.T) {
+			pt.Parallel(it,
+				pt.Table(map[string]int{"c": 3, "a": 1, "b": 2}, func(t *testing.T, c int) {
+					events.add(fmt.Sprintf("%s=%d", t.Name(), c))
+				}),
+			)
+		})
+		actual := events.get()
+		sort.Strings(actual)
+		prefix := t.Name() + "/internal/"
+		assertEvents(t, actual, prefix+"a=1", prefix+"b=2", prefix+"c=3")
+	})
+	t.Run("should log failed case", func(t *testing.T) {
+		t.Parallel()
+		output, err := runTestdata(t, "each")
+		if err == nil {
+			t.Fatalf("go test succeeded:\n%s", output)
+		}
+		assertContains(t, output,
+			"--- FAIL: TestTable/fails ",
+			`case #0 "fails": -1`,
+			"--- PASS: TestTable/ok ",
+		)
+		assertNotContains(t, output, `case #1 "ok"`)
+	})
+}
@@ -0,0 +1,92 @@
+package pt
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// envResource is the resource which represents environment variables of the process.
+// Every test run by pt claims it in shared mode (see [resourceLocks.acquire]), tests with [Env] claim it in exclusive mode.
+const envResource = "pt:environment"
+
+/*
+Env is an [Option] which sets the environment variable for the duration of the test
+(including BeforeEach and AfterEach hooks) and restores it afterwards.
+It replaces [testing.T.Setenv], which panics in parallel tests.
+
+Environment variables are global for the process, so the test runs in an exclusive slot:
+it waits until all running tests of pt are finished, and other tests of pt wait until it is finished.
+Note that tests not run by pt are not paused, and BeforeAll and AfterAll hooks don't see the variable.
+
+When applied to a group, each test of the group (including nested groups) gets the variable,
+unless the test or a nested group sets the same variable.
+
+	pt.Test("should read config from env", testConfig, pt.Env("CONFIG_PATH", "testdata/config.json"))
+
+A test waiting for the exclusive slot blocks tests which start later, so it is not delayed forever by overlapping tests.
+A test which runs tests by pt inside of its body (e.g. nested [Parallel] call, also wrapped by [testing.T.Run])
+stops blocking tests with Env at that moment, so that nested tests with Env don't wait for it forever.
+A test with Env itself can't run tests by pt inside of its body, such test fails.
+*/
+func Env(key string, value string) Option {
+	if key == "" || strings.ContainsAny(key, "=\x00") {
+		panic(fmt.Sprintf("argument key %q is not a valid name of environment variable", key))
+	}
+	return func(target *node) {
+		// copy variables, because target can be a copy of another node made by With
+		env := make(map[string]string, len(target.env)+1)
+		for k, v := range target.env {
+			env[k] = v
+		}
+		env[key] = value
+		target.env = env
+	}
+}
+
+// envOf returns environment variables of test n and all groups it belongs to, inner settings win.
+func (s *scope) envOf(n *node) map[string]string {
+	var owners []*node
+	for ; s != nil; s = s.parent {
+		if s.owner != nil {
+			owners = append(owners, s.owner)
+		}
+	}
+	env := make(map[string]string)
+	for i := len(owners) - 1; i >= 0; i-- {
+		for k, v := range owners[i].env {
+			env[k] = v
+		}
+	}
+	if n != nil {
+		for k, v := range n.env {
+			env[k] = v
+		}
+	}
+	return env
+}
+
+// setEnv sets environment variables for t, they are restored when t is finished.
+func setEnv(t *testing.T, env map[string]string) {
+	keys := make([]string, 0, len(env))
+	for key := range env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		key := key
+		previous, exists := os.LookupEnv(key)
+		if err := os.Setenv(key, env[key]); err != nil {
+			t.Fatalf("failed to set environment variable %s: %v", key, err)
+		}
+		t.Cleanup(func() {
+			if exists {
+				_ = os.Setenv(key, previous)
+			} else {
+				_ = os.Unsetenv(key)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package pt_test
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/maratori/pt"
+)
+
+func TestEnv(t *testing.T) {
+	t.Parallel()
+	t.Run("should panic on empty key", func(t *testing.T) {
+		t.Parallel()
+		defer assertPanic(t, `argument key "" is not a valid name of environment variable`)
+		pt.Env("", "value")
+	})
+	t.Run("should panic on key with =", func(t *testing.T) {
+		t.Parallel()
+		defer assertPanic(t, `argument key "A=B" is not a valid name of environment variable`)
+		pt.Env("A=B", "value")
+	})
+	t.Run("should set and restore variables", func(t *testing.T) {
+		t.Parallel()
+		var events eventLog
+		t.Run("internal", func(it *testing.T) {
+			pt.Parallel(it,
+				pt.With(pt.Group("group",
+					pt.BeforeEach(func(t *testing.T) {
+						events.add("before " + os.Getenv("PT_TEST_ENV_GROUP") + " " + os.Getenv("PT_TEST_ENV_TEST"))
+					}),
+					pt.Test("test", func(t *testing.T) {
+						events.add("test " + os.Getenv("PT_TEST_ENV_GROUP") + " " + os.Getenv("PT_TEST_ENV_TEST"))
+					}, pt.Env("PT_TEST_ENV_TEST", "test"), pt.Env("PT_TEST_ENV_GROUP", "overridden")),
+				), pt.Env("PT_TEST_ENV_GROUP", "group")),
+			)
+		})
+		assertEvents(t, events.get(), "before overridden test", "test overridden test")
+		for _, key := range []string{"PT_TEST_ENV_GROUP", "PT_TEST_ENV_TEST"} {
+			if _, ok := os.LookupEnv(key); ok {
+				t.Errorf("variable %s is not restored", key)
+			}
+		}
+	})
+	t.Run("should not overlap with other tests", func(t *testing.T) {
+		t.Parallel()
+		var events eventLog
+		other := func(t *testing.T) {
+			time.Sleep(10 * time.Millisecond)
+			if os.Getenv("PT_TEST_ENV_EXCLUSIVE") != "" {
+				events.add("variable is visible in other test")
+			}
+		}
+		t.Run("internal", func(it *testing.T) {
+			pt.Parallel(it,
+				pt.Test("other 1", other),
+				pt.Test("env", func(t *testing.T) {
+					time.Sleep(50 * time.Millisecond)
+				}, pt.Env("PT_TEST_ENV_EXCLUSIVE", "value")),
+				pt.Test("other 2", other),
+				pt.Test("other 3", other),
+			)
+		})
+		assertEvents(t, events.get())
+	})
+	for _, parallel := range []int{1, 2} {
+		parallel := parallel
+		t.Run(fmt.Sprintf("should run nested tests with -parallel=%d", parallel), func(t *testing.T) {
+			t.Parallel()
+			output, err := runTestdata(t, "env", fmt.Sprintf("GOFLAGS=-parallel=%d -timeout=1m", parallel))
+			if err == nil {
+				t.Fatalf("go test succeeded:\n%s", output)
+			}
+			assertContains(t, output,
+				"--- PASS: TestNested ",
+				"--- PASS: TestNested/outer/inner ",
+				"--- PASS: TestNested/outer_of_test_with_Env/inner ",
+				"--- PASS: TestNested/outer_of_wrapped_test_with_Env/wrap/inner ",
+				"tests can't be run by pt inside of the body of a test which claims resources or has Env",
+				"--- FAIL: TestNestedInEnv/outer ",
+			)
+			assertNotContains(t, output, "test nested in test with Env is run")
+		})
+	}
+}
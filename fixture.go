@@ -0,0 +1,151 @@
+package pt
+
+import (
+	"sync"
+	"testing"
+)
+
+/*
+Fixture is a value which is lazily created on first use and shared by tests of a tree.
+Use [NewFixture] to create it and [Fixture.Get] to get the value inside a test.
+
+By default, the value is shared by all tests of the tree started by a single [Parallel] or [PackageParallel] call.
+Pass [Provide] to a [Group] to create a separate value for tests of the group.
+
+	var db = pt.NewFixture(openDB, closeDB)
+
+	func TestRepo(t *testing.T) {
+		pt.PackageParallel(t,
+			pt.Test("should insert", func(t *testing.T) {
+				conn := db.Get(t)
+				// test code
+			}),
+			pt.Test("should delete", func(t *testing.T) {
+				conn := db.Get(t) // the same value as in the test above
+				// test code
+			}),
+		)
+	}
+
+The value is torn down only after the last test that uses it is finished.
+*/
+type Fixture[T any] struct {
+	setup    func(t *testing.T) T
+	teardown func(t *testing.T, value T)
+}
+
+// NewFixture is a constructor of [Fixture].
+// The setup function receives *testing.T of the test which calls [Fixture.Get] first.
+// The teardown function is optional and receives *testing.T of the test or group which releases the value last.
+func NewFixture[T any](setup func(t *testing.T) T, teardown func(t *testing.T, value T)) *Fixture[T] {
+	if setup == nil {
+		panic("argument setup func(t *testing.T) T can not be nil")
+	}
+	return &Fixture[T]{
+		setup:    setup,
+		teardown: teardown,
+	}
+}
+
+// Provide makes the group create a separate value of the fixture for its tests (including nested groups).
+// It is designed to be an argument of [Group], [Serial], [Parallel], [Sequential] and [PackageParallel].
+func Provide[T any](fixture *Fixture[T]) testing.InternalTest {
+	if fixture == nil {
+		panic("argument fixture *Fixture[T] can not be nil")
+	}
+	return register(&node{
+		name:    "Provide",
+		kind:    KindProvide,
+		fixture: fixture,
+	})
+}
+
+// Get returns the value of the fixture creating it if necessary.
+// It can be called only from a test run by pt.
+// The test holds the value until it is finished.
+// If setup of the value fails, all tests using the value fail.
+func (f *Fixture[T]) Get(t *testing.T) T {
+	if t == nil {
+		panic("argument t *testing.T can not be nil")
+	}
+	s := scopeOf(t)
+	if s == nil {
+		t.Fatal("fixture can be used only in tests run by pt")
+	}
+	instance := fixtureInstanceOf(s.fixtureOwner(f), f)
+	instance.acquire()
+	t.Cleanup(func() { instance.release(t) })
+	instance.once.Do(func() {
+		instance.value = f.setup(t)
+		instance.created = true
+	})
+	if !instance.created {
+		t.Fatal("fixture setup failed")
+	}
+	return instance.value
+}
+
+// fixtureInstance is a value of [Fixture] in a particular scope.
+type fixtureInstance[T any] struct {
+	fixture *Fixture[T]
+	once    sync.Once
+	value   T
+	created bool
+
+	mu   sync.Mutex
+	refs int
+}
+
+// fixtureInstanceOf returns the instance of fixture owned by s.
+// The scope holds a reference to the instance until all its tests are finished.
+func fixtureInstanceOf[T any](s *scope, fixture *Fixture[T]) *fixtureInstance[T] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if instance, ok := s.fixtures[fixture].(*fixtureInstance[T]); ok {
+		return instance
+	}
+	instance := &fixtureInstance[T]{
+		fixture: fixture,
+		refs:    1,
+	}
+	if s.fixtures == nil {
+		s.fixtures = make(map[any]any)
+	}
+	s.fixtures[fixture] = instance
+	s.t.Cleanup(func() { instance.release(s.t) })
+	return instance
+}
+
+func (i *fixtureInstance[T]) acquire() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.refs++
+}
+
+func (i *fixtureInstance[T]) release(t *testing.T) {
+	i.mu.Lock()
+	i.refs--
+	last := i.refs == 0
+	i.mu.Unlock()
+	if last && i.created && i.fixture.teardown != nil {
+		i.fixture.teardown(t, i.value)
+	}
+}
+
+// provide makes s the owner of fixture for its tests.
+func (s *scope) provide(fixture any) {
+	if s.provided == nil {
+		s.provided = make(map[any]bool)
+	}
+	s.provided[fixture] = true
+}
+
+// fixtureOwner returns the closest scope which provides fixture or the root scope.
+func (s *scope) fixtureOwner(fixture any) *scope {
+	for ; s.parent != nil; s = s.parent {
+		if s.provided[fixture] {
+			return s
+		}
+	}
+	return s
+}
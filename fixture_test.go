@@ -0,0 +1,117 @@
+package pt_test
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/maratori/pt"
+)
+
+func TestFixture(t *testing.T) {
+	t.Parallel()
+	t.Run("should panic on nil setup", func(t *testing.T) {
+		t.Parallel()
+		defer assertPanic(t, "argument setup func(t *testing.T) T can not be nil")
+		pt.NewFixture[int](nil, nil)
+	})
+	t.Run("should panic on nil fixture", func(t *testing.T) {
+		t.Parallel()
+		defer assertPanic(t, "argument fixture *Fixture[T] can not be nil")
+		pt.Provide[int](nil)
+	})
+	t.Run("should not create value if not used", func(t *testing.T) {
+		t.Parallel()
+		var created int32
+		fixture := pt.NewFixture(func(*testing.T) int {
+			atomic.AddInt32(&created, 1)
+			return 0
+		}, nil)
+		t.Run("internal", func(it *testing.T) {
+			pt.Parallel(it,
+				pt.Provide(fixture),
+				pt.Test("test", func(*testing.T) {}),
+			)
+		})
+		if created != 0 {
+			t.Error("value is created")
+		}
+	})
+	t.Run("should share value between tests", func(t *testing.T) {
+		t.Parallel()
+		var created, tornDown int32
+		fixture := pt.NewFixture(func(*testing.T) int32 {
+			return atomic.AddInt32(&created, 1)
+		}, func(*testing.T, int32) {
+			atomic.AddInt32(&tornDown, 1)
+		})
+		var values [3]int32
+		t.Run("internal", func(it *testing.T) {
+			pt.Parallel(it,
+				pt.Test("test1", func(t *testing.T) { values[0] = fixture.Get(t) }),
+				pt.Test("test2", func(t *testing.T) { values[1] = fixture.Get(t) }),
+				pt.Group("group",
+					pt.Test("test3", func(t *testing.T) { values[2] = fixture.Get(t) }),
+				),
+			)
+		})
+		if created != 1 {
+			t.Errorf("value is created %d times", created)
+		}
+		if tornDown != 1 {
+			t.Errorf("value is torn down %d times", tornDown)
+		}
+		if values != [3]int32{1, 1, 1} {
+			t.Errorf("unexpected values %v", values)
+		}
+	})
+	t.Run("should create separate value for group with Provide", func(t *testing.T) {
+		t.Parallel()
+		var created int32
+		fixture := pt.NewFixture(func(*testing.T) int32 {
+			return atomic.AddInt32(&created, 1)
+		}, nil)
+		var outer, inner1, inner2 int32
+		t.Run("internal", func(it *testing.T) {
+			pt.Parallel(it,
+				pt.Test("test", func(t *testing.T) { outer = fixture.Get(t) }),
+				pt.Group("group",
+					pt.Provide(fixture),
+					pt.Test("test1", func(t *testing.T) { inner1 = fixture.Get(t) }),
+					pt.Test("test2", func(t *testing.T) { inner2 = fixture.Get(t) }),
+				),
+			)
+		})
+		if created != 2 {
+			t.Errorf("value is created %d times", created)
+		}
+		if outer == inner1 || inner1 != inner2 {
+			t.Errorf("unexpected values outer=%d inner1=%d inner2=%d", outer, inner1, inner2)
+		}
+	})
+	t.Run("should tear down value after all tests are finished", func(t *testing.T) {
+		t.Parallel()
+		var events eventLog
+		fixture := pt.NewFixture(func(*testing.T) int {
+			events.add("setup")
+			return 0
+		}, func(*testing.T, int) {
+			events.add("teardown")
+		})
+		t.Run("internal", func(it *testing.T) {
+			pt.Parallel(it,
+				pt.AfterAll(events.hook("after all")),
+				pt.Test("test1", func(t *testing.T) {
+					fixture.Get(t)
+				}),
+				pt.Test("test2", func(t *testing.T) {
+					events.add("test2")
+				}),
+			)
+		})
+		actual := events.get()
+		if len(actual) != 4 {
+			t.Fatalf("unexpected events %q", actual)
+		}
+		assertEvents(t, actual[2:], "teardown", "after all")
+	})
+}
@@ -0,0 +1,181 @@
+package pt
+
+import (
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// ciEnv is the environment variable set by most CI systems.
+// Focused tests fail if it is set, so that they are never merged.
+const ciEnv = "CI"
+
+/*
+FTest is the same as [Test], but the test is focused.
+If there is at least one focused test or group in the package, all tests run by pt which are not focused are skipped.
+It is designed to be used temporarily while debugging, so focused tests fail if environment variable CI is set.
+
+Focused tests are detected by parsing test files of the package in the current directory,
+and by calls of FTest and FGroup made before the test is started.
+*/
+func FTest(name string, test func(t *testing.T), opts ...Option) testing.InternalTest {
+	return Test(name, test, append(opts, focused)...)
+}
+
+// FGroup is the same as [Group], but the group is focused, so all its tests are focused.
+// See [FTest] for details.
+func FGroup(name string, tests ...testing.InternalTest) testing.InternalTest {
+	return With(Group(name, tests...), focused)
+}
+
+// XTest is the same as [Test], but the test is pending.
+// Pending test is skipped, its code is not executed.
+func XTest(name string, test func(t *testing.T), opts ...Option) testing.InternalTest {
+	return Test(name, test, append(opts, pending)...)
+}
+
+// XGroup is the same as [Group], but the group is pending.
+// Pending group is skipped, its tests and hooks are not executed.
+func XGroup(name string, tests ...testing.InternalTest) testing.InternalTest {
+	return With(Group(name, tests...), pending)
+}
+
+func focused(target *node) {
+	target.focused = true
+	atomic.StoreInt32(&focusDeclared, 1)
+}
+
+func pending(target *node) {
+	target.pending = true
+}
+
+// focusDeclared is 1 if FTest or FGroup is called.
+var focusDeclared int32 //nolint:gochecknoglobals // focus affects all tests in package
+
+// focusInSources is true if FTest or FGroup is called in test files of the package.
+var focusInSources struct { //nolint:gochecknoglobals // sources are parsed once
+	once  sync.Once
+	found bool
+}
+
+// skipUnfocused skips the test n if there are focused tests in the package,
+// but neither n nor groups it belongs to are focused.
+// It fails the focused test on CI.
+func (s *scope) skipUnfocused(t *testing.T, n *node) {
+	if s.isFocused(n) {
+		if ci := os.Getenv(ciEnv); ci != "" && ci != "false" {
+			t.Fatalf("focused test must not be run on CI (environment variable %s=%s)", ciEnv, ci)
+		}
+		return
+	}
+	if focusActive() {
+		t.Skip("skipped because there are focused tests in the package")
+	}
+}
+
+// skipUnfocusedGroup skips the group n if there are focused tests in the package,
+// but neither n, groups it belongs to nor any of its descendants are focused,
+// so that hooks and fixtures of the group are not run in vain.
+func (s *scope) skipUnfocusedGroup(t *testing.T, n *node) {
+	if s.isFocused(n) || n.anyFocused() || !focusActive() {
+		return
+	}
+	t.Skip("skipped because there are focused tests in the package, no tests of the group are focused")
+}
+
+// focusActive returns true if there are focused tests in the package.
+func focusActive() bool {
+	focusInSources.once.Do(func() {
+		focusInSources.found = hasFocusCalls(".")
+	})
+	return atomic.LoadInt32(&focusDeclared) == 1 || focusInSources.found
+}
+
+// anyFocused returns true if at least one descendant of n is focused.
+func (n *node) anyFocused() bool {
+	for _, child := range n.children {
+		if c := lookup(child); c != nil && (c.focused || c.anyFocused()) {
+			return true
+		}
+	}
+	return false
+}
+
+// isFocused returns true if n or any group it belongs to is focused.
+func (s *scope) isFocused(n *node) bool {
+	if n != nil && n.focused {
+		return true
+	}
+	for ; s != nil; s = s.parent {
+		if s.owner != nil && s.owner.focused {
+			return true
+		}
+	}
+	return false
+}
+
+// hasFocusCalls returns true if go test files in dir call FTest or FGroup from pt.
+// Test files excluded by build constraints are not parsed.
+func hasFocusCalls(dir string) bool {
+	paths, err := filepath.Glob(filepath.Join(dir, "*_test.go"))
+	if err != nil {
+		return false
+	}
+	for _, path := range paths {
+		if ok, err := build.Default.MatchFile(dir, filepath.Base(path)); err != nil || !ok {
+			continue
+		}
+		if fileHasFocusCalls(path) {
+			return true
+		}
+	}
+	return false
+}
+
+func fileHasFocusCalls(path string) bool {
+	file, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.SkipObjectResolution)
+	if err != nil {
+		return false
+	}
+	imported := ""
+	for _, spec := range file.Imports {
+		if importPath, err := strconv.Unquote(spec.Path.Value); err != nil || importPath != "github.com/maratori/pt" {
+			continue
+		}
+		imported = "pt"
+		if spec.Name != nil {
+			imported = spec.Name.Name
+		}
+	}
+	if imported == "" {
+		return false
+	}
+	found := false
+	ast.Inspect(file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || found {
+			return !found
+		}
+		switch fun := call.Fun.(type) {
+		case *ast.SelectorExpr:
+			if x, ok := fun.X.(*ast.Ident); ok && x.Name == imported {
+				found = isFocusFunc(fun.Sel.Name)
+			}
+		case *ast.Ident:
+			found = imported == "." && isFocusFunc(fun.Name)
+		}
+		return !found
+	})
+	return found
+}
+
+func isFocusFunc(name string) bool {
+	return name == "FTest" || name == "FGroup"
+}
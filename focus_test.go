@@ -0,0 +1,65 @@
+package pt_test
+
+import (
+	"testing"
+
+	"github.com/maratori/pt"
+)
+
+func TestFocus(t *testing.T) {
+	t.Parallel()
+	t.Run("should run only focused tests", func(t *testing.T) {
+		t.Parallel()
+		output, err := runTestdata(t, "focus", "CI=")
+		if err != nil {
+			t.Fatalf("go test failed: %s\n%s", err, output)
+		}
+		assertContains(t, output,
+			"focused test is run",
+			"test in focused group is run",
+			"skipped because there are focused tests in the package",
+			"hook of group with focused test is run",
+			"--- PASS: TestFocused/group_with_focused_test/nested_focused_test ",
+			"no tests of the group are focused",
+		)
+		assertNotContains(t, output,
+			"unfocused test is run",
+			"unfocused test in group is run",
+			"hook of unfocused group is run",
+		)
+	})
+	t.Run("should fail focused tests on CI", func(t *testing.T) {
+		t.Parallel()
+		output, err := runTestdata(t, "focus", "CI=true")
+		if err == nil {
+			t.Fatalf("go test succeeded:\n%s", output)
+		}
+		assertContains(t, output, "focused test must not be run on CI (environment variable CI=true)")
+	})
+}
+
+func TestPending(t *testing.T) {
+	t.Parallel()
+	t.Run("should skip pending test", func(t *testing.T) {
+		t.Parallel()
+		called := false
+		t.Run("internal", func(it *testing.T) {
+			pt.Parallel(it,
+				pt.XTest("", func(*testing.T) { called = true }),
+				pt.XGroup("",
+					pt.BeforeAll(func(*testing.T) { called = true }),
+					pt.Test("", func(*testing.T) { called = true }),
+				),
+			)
+		})
+		if called {
+			t.Error("pending test is called")
+		}
+	})
+	t.Run("should return right name", func(t *testing.T) {
+		t.Parallel()
+		if pt.XTest("abc", func(*testing.T) {}).Name != "abc" || pt.XGroup("abc").Name != "abc" {
+			t.Error("name is wrong")
+		}
+	})
+}
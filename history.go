@@ -0,0 +1,174 @@
+package pt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+// historyEnv is the environment variable with the path of duration history file.
+const historyEnv = "PT_HISTORY"
+
+// historyFile is the content of duration history file.
+type historyFile struct {
+	Tests map[string]historyEntry `json:"tests"`
+}
+
+// historyEntry is the duration of a test recorded on the last run.
+type historyEntry struct {
+	Seconds float64 `json:"seconds"`
+	Unit    bool    `json:"unit,omitempty"` // the test is sharded as a whole (see [isShardUnit])
+}
+
+// history holds duration history read from the file in PT_HISTORY environment variable.
+var history struct { //nolint:gochecknoglobals // history is read once
+	once   sync.Once
+	tests  map[string]historyEntry
+	shards map[string]uint32 // balanced shard of every test sharded as a whole, nil if sharding is disabled
+}
+
+// loadHistory reads duration history once, a missing file is an empty history.
+func loadHistory() {
+	history.once.Do(func() {
+		path := os.Getenv(historyEnv)
+		if path == "" {
+			return
+		}
+		tests, err := readHistory(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "pt: failed to read duration history %s: %v\n", path, err)
+		}
+		history.tests = tests
+		if _, count := shardSettings(); count > 0 {
+			history.shards = balanceShards(tests, count)
+		}
+	})
+}
+
+// historySetting returns the path of duration history file to write or empty string if it is not written.
+// Sharded runs don't write the history: every shard would record its own tests only,
+// and shards reading different histories would balance tests differently, so some tests would be run by no shard.
+func historySetting() string {
+	if _, count := shardSettings(); count > 0 {
+		return ""
+	}
+	return reportSetting(historyEnv)
+}
+
+func readHistory(path string) (map[string]historyEntry, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var file historyFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+	return file.Tests, nil
+}
+
+// historyShard returns the balanced shard of the test with provided full name.
+// It returns false if the test is unknown, so the shard should be chosen by hash.
+func historyShard(name string) (uint32, bool) {
+	loadHistory()
+	index, ok := history.shards[name]
+	return index, ok
+}
+
+// balanceShards distributes known tests sharded as a whole between count shards,
+// so that shards take approximately the same time.
+// The longest test is assigned to the shard with the least total duration first.
+// The result depends only on the history, so all shards compute the same distribution.
+func balanceShards(tests map[string]historyEntry, count uint32) map[string]uint32 {
+	names := make([]string, 0, len(tests))
+	for name, entry := range tests {
+		if entry.Unit {
+			names = append(names, name)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		a, b := tests[names[i]].Seconds, tests[names[j]].Seconds
+		if a != b {
+			return a > b
+		}
+		return names[i] < names[j]
+	})
+	loads := make([]float64, count)
+	shards := make(map[string]uint32, len(names))
+	for _, name := range names {
+		lightest := uint32(0)
+		for i := range loads {
+			if loads[i] < loads[lightest] {
+				lightest = uint32(i)
+			}
+		}
+		loads[lightest] += tests[name].Seconds
+		shards[name] = lightest
+	}
+	return shards
+}
+
+// sortByHistory orders tests of t, so that the longest tests according to the history start first.
+// Unknown tests keep declaration order and start after known ones.
+func sortByHistory(t *testing.T, tests []testing.InternalTest) {
+	loadHistory()
+	if len(history.tests) == 0 {
+		return
+	}
+	type item struct {
+		test    testing.InternalTest
+		seconds float64
+	}
+	items := make([]item, 0, len(tests))
+	seen := make(map[string]int)
+	for _, test := range tests {
+		name := uniqueName(seen, t.Name(), rewriteName(test.Name))
+		items = append(items, item{test: test, seconds: history.tests[name].Seconds})
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].seconds > items[j].seconds
+	})
+	for i, item := range items {
+		tests[i] = item.test
+	}
+}
+
+// writeHistory writes durations of executed tests to path atomically.
+// Durations of tests which were not executed (e.g. filtered by -run or belong to another shard) are kept.
+func (r *reporter) writeHistory(path string) error {
+	loadHistory()
+	tests := make(map[string]historyEntry, len(history.tests))
+	for name, entry := range history.tests {
+		tests[name] = entry
+	}
+	r.mu.Lock()
+	var collect func(list []*reportedTest)
+	collect = func(list []*reportedTest) {
+		for _, test := range list {
+			if test.Status == "skip" || test.End.IsZero() {
+				continue
+			}
+			tests[test.Name] = historyEntry{
+				Seconds: test.duration().Round(time.Millisecond).Seconds(),
+				Unit:    test.unit,
+			}
+			collect(test.Children)
+		}
+	}
+	collect(r.roots)
+	r.mu.Unlock()
+	data, err := json.MarshalIndent(historyFile{Tests: tests}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomically(path, data)
+}
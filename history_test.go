@@ -0,0 +1,101 @@
+package pt_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHistory(t *testing.T) {
+	t.Parallel()
+	t.Run("should record durations", func(t *testing.T) {
+		t.Parallel()
+		path := filepath.Join(t.TempDir(), "history.json")
+		output, err := runTestdata(t, "history", "PT_HISTORY="+path, "PT_SHARD=")
+		if err != nil {
+			t.Fatalf("go test failed: %s\n%s", err, output)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var history struct {
+			Tests map[string]struct {
+				Seconds float64 `json:"seconds"`
+				Unit    bool    `json:"unit"`
+			} `json:"tests"`
+		}
+		if err := json.Unmarshal(data, &history); err != nil {
+			t.Fatal(err)
+		}
+		if slow := history.Tests["TestHistory/group/slow"]; slow.Seconds < 0.05 || !slow.Unit {
+			t.Errorf("wrong duration of slow test:\n%s", data)
+		}
+		if group := history.Tests["TestHistory/group"]; group.Seconds < 0.05 || group.Unit {
+			t.Errorf("wrong duration of group:\n%s", data)
+		}
+	})
+	t.Run("should start longest tests first", func(t *testing.T) {
+		t.Parallel()
+		path := writeHistory(t)
+		output, err := runTestdata(t, "history", "PT_HISTORY="+path, "PT_SHARD=")
+		if err != nil {
+			t.Fatalf("go test failed: %s\n%s", err, output)
+		}
+		order := startOrder(output, "TestHistory/group/")
+		if strings.Join(order, ",") != "slow,medium_1,medium_2,fast" {
+			t.Errorf("wrong order %v", order)
+		}
+	})
+	t.Run("should balance shards", func(t *testing.T) {
+		t.Parallel()
+		path := writeHistory(t)
+		before, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// shards are run one after another with the same file, like CI jobs restoring it from cache
+		output1, err := runTestdata(t, "history", "PT_HISTORY="+path, "PT_SHARD=1/2")
+		if err != nil {
+			t.Fatalf("go test failed: %s\n%s", err, output1)
+		}
+		output2, err := runTestdata(t, "history", "PT_HISTORY="+path, "PT_SHARD=2/2")
+		if err != nil {
+			t.Fatalf("go test failed: %s\n%s", err, output2)
+		}
+		assertContains(t, output1, "--- PASS: TestHistory/group/slow ", "--- SKIP: TestHistory/group/medium_1 ")
+		assertContains(t, output2, "--- SKIP: TestHistory/group/slow ", "--- PASS: TestHistory/group/medium_1 ",
+			"--- PASS: TestHistory/group/medium_2 ")
+		for _, name := range []string{"fast", "medium_1", "medium_2", "slow"} {
+			passed := "--- PASS: TestHistory/group/" + name + " "
+			if count := strings.Count(output1, passed) + strings.Count(output2, passed); count != 1 {
+				t.Errorf("test %s is run by %d shards", name, count)
+			}
+		}
+		after, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(after) != string(before) {
+			t.Errorf("history is updated by sharded run:\n%s", after)
+		}
+	})
+}
+
+// writeHistory writes duration history file for testdata/history package.
+func writeHistory(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), "history.json")
+	data := `{"tests": {
+		"TestHistory/group": {"seconds": 3},
+		"TestHistory/group/fast": {"seconds": 0.1, "unit": true},
+		"TestHistory/group/medium_1": {"seconds": 1, "unit": true},
+		"TestHistory/group/medium_2": {"seconds": 0.9, "unit": true},
+		"TestHistory/group/slow": {"seconds": 2, "unit": true}
+	}}`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
@@ -0,0 +1,85 @@
+package pt
+
+import (
+	"testing"
+)
+
+// hookKind describes when a hook is run.
+type hookKind int
+
+const (
+	hookBeforeAll hookKind = iota
+	hookAfterAll
+	hookBeforeEach
+	hookAfterEach
+)
+
+func (k hookKind) String() string {
+	switch k {
+	case hookBeforeAll:
+		return "BeforeAll"
+	case hookAfterAll:
+		return "AfterAll"
+	case hookBeforeEach:
+		return "BeforeEach"
+	case hookAfterEach:
+		return "AfterEach"
+	}
+	return "unknown"
+}
+
+/*
+BeforeAll is a constructor of a hook which runs once before all tests of the group.
+It is designed to be an argument of [Group], [Serial], [Parallel], [Sequential] and [PackageParallel].
+The hook receives *testing.T of the group, so calling t.FailNow() inside the hook prevents tests of the group from running.
+
+	pt.Group("with database",
+		pt.BeforeAll(func(t *testing.T) {
+			// create database
+		}),
+		pt.Test("should do something", func(t *testing.T) {
+			// test code
+		}),
+	)
+*/
+func BeforeAll(hook func(t *testing.T)) testing.InternalTest {
+	return newHook(hookBeforeAll, hook)
+}
+
+// AfterAll is a constructor of a hook which runs once after all tests of the group are finished.
+// It is designed to be an argument of [Group], [Serial], [Parallel], [Sequential] and [PackageParallel].
+// Even though [Parallel] is non-blocking, the hook is run only after all parallel tests of the group are finished.
+// Several AfterAll hooks of the same group are run in reverse order like [testing.T.Cleanup].
+func AfterAll(hook func(t *testing.T)) testing.InternalTest {
+	return newHook(hookAfterAll, hook)
+}
+
+// BeforeEach is a constructor of a hook which runs before each test of the group.
+// It is designed to be an argument of [Group], [Serial], [Parallel], [Sequential] and [PackageParallel].
+// The hook is inherited by nested groups, so it runs before each [Test] in the whole subtree.
+// Hooks of outer groups run first, hooks of the same group run in declaration order.
+// The hook receives *testing.T of the test and runs in the same goroutine right before the test.
+func BeforeEach(hook func(t *testing.T)) testing.InternalTest {
+	return newHook(hookBeforeEach, hook)
+}
+
+// AfterEach is a constructor of a hook which runs after each test of the group.
+// It is designed to be an argument of [Group], [Serial], [Parallel], [Sequential] and [PackageParallel].
+// The hook is inherited by nested groups, so it runs after each [Test] in the whole subtree.
+// Hooks of inner groups run first, hooks of the same group run in declaration order.
+// The hook runs even if the test or BeforeEach hook fails.
+func AfterEach(hook func(t *testing.T)) testing.InternalTest {
+	return newHook(hookAfterEach, hook)
+}
+
+func newHook(k hookKind, hook func(t *testing.T)) testing.InternalTest {
+	if hook == nil {
+		panic("argument hook func(t *testing.T) can not be nil")
+	}
+	return register(&node{
+		name: k.String(),
+		kind: KindHook,
+		f:    hook,
+		hook: k,
+	})
+}
@@ -0,0 +1,184 @@
+package pt_test
+
+import (
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/maratori/pt"
+)
+
+func TestHooks(t *testing.T) {
+	t.Parallel()
+	t.Run("should panic on nil hook", func(t *testing.T) {
+		t.Parallel()
+		for _, constructor := range []func(func(*testing.T)) testing.InternalTest{
+			pt.BeforeAll, pt.AfterAll, pt.BeforeEach, pt.AfterEach,
+		} {
+			func() {
+				defer assertPanic(t, "argument hook func(t *testing.T) can not be nil")
+				constructor(nil)
+			}()
+		}
+	})
+	t.Run("should not run hooks as tests", func(t *testing.T) {
+		t.Parallel()
+		var events eventLog
+		t.Run("internal", func(it *testing.T) {
+			pt.Parallel(it,
+				pt.BeforeAll(events.hook("before all")),
+				pt.Test("test", events.hook("test")),
+			)
+			if len(events.get()) != 1 {
+				it.Error("hook is not called synchronously")
+			}
+		})
+		assertEvents(t, events.get(), "before all", "test")
+	})
+	t.Run("should run all hooks in right order", func(t *testing.T) {
+		t.Parallel()
+		var events eventLog
+		t.Run("internal", func(it *testing.T) {
+			pt.Parallel(it,
+				pt.BeforeAll(events.hook("before all")),
+				pt.AfterAll(events.hook("after all")),
+				pt.BeforeEach(events.hook("before each 1")),
+				pt.BeforeEach(events.hook("before each 2")),
+				pt.AfterEach(events.hook("after each 1")),
+				pt.AfterEach(events.hook("after each 2")),
+				pt.Test("test", events.hook("test")),
+			)
+		})
+		assertEvents(t, events.get(),
+			"before all",
+			"before each 1",
+			"before each 2",
+			"test",
+			"after each 1",
+			"after each 2",
+			"after all",
+		)
+	})
+	t.Run("should run AfterAll hooks in reverse order", func(t *testing.T) {
+		t.Parallel()
+		var events eventLog
+		t.Run("internal", func(it *testing.T) {
+			pt.Parallel(it,
+				pt.AfterAll(events.hook("after all 1")),
+				pt.AfterAll(events.hook("after all 2")),
+			)
+		})
+		assertEvents(t, events.get(), "after all 2", "after all 1")
+	})
+	t.Run("should inherit each hooks in nested groups", func(t *testing.T) {
+		t.Parallel()
+		var events eventLog
+		t.Run("internal", func(it *testing.T) {
+			pt.Parallel(it,
+				pt.BeforeEach(events.hook("outer before")),
+				pt.AfterEach(events.hook("outer after")),
+				pt.Group("group",
+					pt.BeforeAll(events.hook("group before all")),
+					pt.BeforeEach(events.hook("inner before")),
+					pt.AfterEach(events.hook("inner after")),
+					pt.Test("test", events.hook("test")),
+				),
+			)
+		})
+		assertEvents(t, events.get(),
+			"group before all",
+			"outer before",
+			"inner before",
+			"test",
+			"inner after",
+			"outer after",
+		)
+	})
+	t.Run("should run each hooks for every test", func(t *testing.T) {
+		t.Parallel()
+		var events eventLog
+		t.Run("internal", func(it *testing.T) {
+			pt.Parallel(it,
+				pt.BeforeEach(func(t *testing.T) { events.add("before " + t.Name()) }),
+				pt.Test("test1", func(*testing.T) {}),
+				pt.Test("test2", func(*testing.T) {}),
+				testing.InternalTest{Name: "test3", F: func(*testing.T) {}},
+			)
+		})
+		actual := events.get()
+		sort.Strings(actual)
+		assertEvents(t, actual,
+			"before "+t.Name()+"/internal/test1",
+			"before "+t.Name()+"/internal/test2",
+			"before "+t.Name()+"/internal/test3",
+		)
+	})
+	t.Run("should run AfterEach if BeforeEach stops test", func(t *testing.T) {
+		t.Parallel()
+		var events eventLog
+		t.Run("internal", func(it *testing.T) {
+			pt.Parallel(it,
+				pt.BeforeEach(func(t *testing.T) {
+					events.add("before")
+					t.SkipNow()
+				}),
+				pt.AfterEach(events.hook("after")),
+				pt.Test("test", events.hook("test")),
+			)
+		})
+		assertEvents(t, events.get(), "before", "after")
+	})
+}
+
+func TestHooks2(t *testing.T) {
+	// Do not call t.Parallel() because test measures execution time
+	t.Run("should run AfterAll after parallel tests", func(t *testing.T) {
+		singleTestDuration := 1 * time.Second
+		var events eventLog
+		t.Run("internal", func(it *testing.T) {
+			pt.Parallel(it,
+				pt.AfterAll(events.hook("after all")),
+				pt.Test("test1", func(*testing.T) {
+					time.Sleep(singleTestDuration)
+					events.add("test")
+				}),
+				pt.Test("test2", func(*testing.T) {
+					time.Sleep(singleTestDuration)
+					events.add("test")
+				}),
+			)
+		})
+		assertEvents(t, events.get(), "test", "test", "after all")
+	})
+}
+
+type eventLog struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (l *eventLog) add(event string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = append(l.events, event)
+}
+
+func (l *eventLog) hook(event string) func(t *testing.T) {
+	return func(*testing.T) {
+		l.add(event)
+	}
+}
+
+func (l *eventLog) get() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string(nil), l.events...)
+}
+
+func assertEvents(t *testing.T, actual []string, expected ...string) {
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("unexpected events:\nactual:   %q\nexpected: %q", actual, expected)
+	}
+}
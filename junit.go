@@ -0,0 +1,153 @@
+package pt
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// junitEnv is the environment variable with the path of JUnit XML report written by [Main].
+const junitEnv = "PT_JUNIT"
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name       string          `xml:"name,attr"`
+	Classname  string          `xml:"classname,attr"`
+	Time       string          `xml:"time,attr"`
+	File       string          `xml:"file,attr,omitempty"`
+	Line       int             `xml:"line,attr,omitempty"`
+	Properties []junitProperty `xml:"properties>property,omitempty"`
+	Failure    *junitMessage   `xml:"failure"`
+	Skipped    *junitMessage   `xml:"skipped"`
+	SystemOut  string          `xml:"system-out,omitempty"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// writeJUnit writes JUnit XML report to path atomically.
+// Tests with subtests (top level tests, groups, repeated tests) are testsuites named by full test name,
+// tests without subtests are testcases of the closest testsuite.
+func (r *reporter) writeJUnit(path string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.attachLogs(r.roots)
+	var suites junitTestSuites
+	for _, root := range r.roots {
+		suites.add(root)
+	}
+	data, err := xml.MarshalIndent(suites, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomically(path, append([]byte(xml.Header), data...))
+}
+
+// add adds testsuite for the test and testsuites for all its descendants with subtests.
+func (s *junitTestSuites) add(test *reportedTest) {
+	suite := junitTestSuite{
+		Name:      test.Name,
+		Time:      junitTime(test.End.Sub(test.Start)),
+		Timestamp: test.Start.Format(time.RFC3339),
+	}
+	failed := false
+	for _, child := range test.Children {
+		if len(child.Children) == 0 {
+			suite.Cases = append(suite.Cases, newJUnitTestCase(test.Name, child))
+		}
+		failed = failed || child.Status == "fail"
+	}
+	if test.Status == "fail" && !failed {
+		// the failure is not caused by a test of the suite (e.g. a hook of the group failed)
+		suite.Cases = append(suite.Cases, newJUnitTestCase(test.Name, test))
+	}
+	for _, c := range suite.Cases {
+		suite.Tests++
+		if c.Failure != nil {
+			suite.Failures++
+		}
+		if c.Skipped != nil {
+			suite.Skipped++
+		}
+	}
+	if len(suite.Cases) > 0 {
+		s.Suites = append(s.Suites, suite)
+	}
+	for _, child := range test.Children {
+		if len(child.Children) > 0 {
+			s.add(child)
+		}
+	}
+}
+
+func newJUnitTestCase(suite string, test *reportedTest) junitTestCase {
+	name := strings.TrimPrefix(test.Name, suite+"/")
+	c := junitTestCase{
+		Name:      name,
+		Classname: suite,
+		Time:      junitTime(test.End.Sub(test.Start)),
+		File:      test.File,
+		Line:      test.Line,
+	}
+	if test.Attempts > 1 {
+		c.Properties = append(c.Properties, junitProperty{Name: "attempts", Value: strconv.Itoa(test.Attempts)})
+		if test.Status == "pass" {
+			c.Properties = append(c.Properties, junitProperty{Name: "flaky", Value: "true"})
+		}
+	}
+	for _, tag := range test.Tags {
+		c.Properties = append(c.Properties, junitProperty{Name: "tag", Value: tag})
+	}
+	logs := strings.Join(test.Logs, "\n")
+	switch test.Status {
+	case "fail":
+		c.Failure = &junitMessage{Message: logMessage(test.Logs, 0), Text: logs}
+	case "skip":
+		c.Skipped = &junitMessage{Message: logMessage(test.Logs, len(test.Logs)-1)}
+	default:
+		c.SystemOut = logs
+	}
+	return c
+}
+
+// logMessage returns i-th log line without file:line prefix.
+func logMessage(logs []string, i int) string {
+	if i < 0 || i >= len(logs) {
+		return ""
+	}
+	line := logs[i]
+	if prefix := strings.Index(line, ": "); prefix > 0 && strings.Contains(line[:prefix], ".go:") {
+		line = line[prefix+2:]
+	}
+	return line
+}
+
+func junitTime(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
@@ -0,0 +1,67 @@
+package pt
+
+import (
+	"context"
+	"runtime/pprof"
+	"testing"
+	"time"
+)
+
+// leakGracePeriod is how long goroutines started by the test have to finish after the test is finished.
+const leakGracePeriod = time.Second
+
+/*
+DetectLeaks is an [Option] which fails the test if goroutines started by it are still running
+after the test and its cleanups (including AfterEach hooks) are finished. Stacks of such goroutines are logged.
+Goroutines have some time to finish after the context of the test (see [Context]) is cancelled.
+
+Goroutines are attributed to the test by pprof label (see [runtime/pprof.SetGoroutineLabels]),
+which is inherited by goroutines started by the test and by goroutines they start,
+so goroutines of tests running in parallel are not mixed up.
+Goroutines started with other labels (e.g. by [runtime/pprof.Do]) are not attributed to the test.
+
+When applied to a group, each test of the group (including nested groups) is checked.
+
+	pt.With(pt.Group("worker", tests...), pt.DetectLeaks())
+*/
+func DetectLeaks() Option {
+	return func(target *node) {
+		target.detectLeaks = true
+	}
+}
+
+// watchLeaks fails t if goroutines labeled with its name are running after t and its cleanups are finished.
+// It must be called before the context of t is created, so that the context is cancelled before the check.
+func watchLeaks(t *testing.T) {
+	t.Cleanup(func() {
+		// the goroutine of the test itself is labeled, but it is finished after cleanup
+		pprof.SetGoroutineLabels(context.Background())
+		delay := time.Millisecond
+		deadline := time.Now().Add(leakGracePeriod)
+		for {
+			leaked := goroutinesOf(t.Name())
+			if leaked == "" {
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Errorf("goroutines started by the test are still running after it is finished:\n\n%s", leaked)
+				return
+			}
+			time.Sleep(delay)
+			delay *= 2
+		}
+	})
+}
+
+// detectLeaksOf returns true if test n or any group it belongs to has [DetectLeaks] option.
+func (s *scope) detectLeaksOf(n *node) bool {
+	if n != nil && n.detectLeaks {
+		return true
+	}
+	for ; s != nil; s = s.parent {
+		if s.owner != nil && s.owner.detectLeaks {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,39 @@
+package pt_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/maratori/pt"
+)
+
+func TestDetectLeaks(t *testing.T) {
+	t.Parallel()
+	t.Run("should pass if goroutines are finished", func(t *testing.T) {
+		t.Parallel()
+		t.Run("internal", func(it *testing.T) {
+			pt.Parallel(it, pt.With(pt.Group("group",
+				pt.TestCtx("test", func(ctx context.Context, t *testing.T) {
+					go func() { <-ctx.Done() }()
+				}),
+			), pt.DetectLeaks()))
+		})
+	})
+	t.Run("should fail test with leaked goroutines", func(t *testing.T) {
+		t.Parallel()
+		output, err := runTestdata(t, "leak", "GOFLAGS=-parallel=4")
+		if err == nil {
+			t.Fatalf("go test succeeded:\n%s", output)
+		}
+		assertContains(t, output,
+			"goroutines started by the test are still running after it is finished:",
+			`# labels: {"pt.test":"TestLeak/group/leaks"}`,
+			"leak.leak+",
+			"--- FAIL: TestLeak/group/leaks ",
+			"--- PASS: TestLeak/group/stops_on_cancel ",
+			"--- PASS: TestLeak/group/no_goroutines ",
+			"--- PASS: TestLeak/not_detected ",
+		)
+		assertNotContains(t, output, `"pt.test":"TestLeak/not_detected"`)
+	})
+}
@@ -0,0 +1,49 @@
+package pt
+
+import (
+	"testing"
+)
+
+/*
+Limit is an [Option] which caps the number of tests of the group (including tests of nested groups)
+running at the same time. It does not affect other tests in the package, unlike -parallel flag of go test.
+Only tests take slots, groups don't, so limits of nested groups are combined:
+a test of a nested group takes a slot of the nested group and a slot of every outer group with Limit.
+
+	pt.With(pt.Group("calls stub server", tests...), pt.Limit(10))
+
+The slot is taken before BeforeEach hooks and released when the test function returns, before AfterEach hooks.
+A test which runs tests by pt inside of its body (e.g. nested [Parallel] call) releases its slots,
+so that the nested tests can take them.
+Tests waiting for a slot are already started by go test, so they occupy -parallel slots while waiting.
+*/
+func Limit(n int) Option {
+	if n < 1 {
+		panic("argument n must be positive")
+	}
+	return func(target *node) {
+		target.limit = n
+	}
+}
+
+// acquireSlots blocks until t gets a slot in every limited scope it belongs to and returns the function releasing them.
+// Slots of outer scopes are taken first, so tests of different nested groups don't wait for each other in a loop.
+func (s *scope) acquireSlots(t *testing.T) func() {
+	var limited []*scope
+	for ; s != nil; s = s.parent {
+		if s.slots != nil {
+			limited = append(limited, s)
+		}
+	}
+	if len(limited) == 0 {
+		return func() {}
+	}
+	for i := len(limited) - 1; i >= 0; i-- {
+		limited[i].slots <- struct{}{}
+	}
+	return yieldable(t, func() {
+		for _, s := range limited {
+			<-s.slots
+		}
+	})
+}
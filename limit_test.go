@@ -0,0 +1,139 @@
+package pt_test
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/maratori/pt"
+)
+
+func TestLimit(t *testing.T) {
+	t.Parallel()
+	t.Run("should panic on non-positive limit", func(t *testing.T) {
+		t.Parallel()
+		defer assertPanic(t, "argument n must be positive")
+		pt.Limit(0)
+	})
+	t.Run("should limit running tests of group", func(t *testing.T) {
+		t.Parallel()
+		var counter concurrencyCounter
+		meeting := newRendezvous(2)
+		tests := make([]testing.InternalTest, 6)
+		for i := range tests {
+			tests[i] = pt.Test("", func(t *testing.T) {
+				leave := counter.enter()
+				defer leave()
+				meeting.test(t)
+				time.Sleep(10 * time.Millisecond)
+			})
+		}
+		t.Run("internal", func(it *testing.T) {
+			pt.Parallel(it, pt.With(pt.Group("group", tests...), pt.Limit(2)))
+		})
+		if peak := counter.max(); peak != 2 {
+			t.Errorf("max running tests %d != 2", peak)
+		}
+	})
+	t.Run("should apply nested limits together", func(t *testing.T) {
+		t.Parallel()
+		var all, inner concurrencyCounter
+		innerTest := func(t *testing.T) {
+			leave := all.enter()
+			defer leave()
+			inner.test(100 * time.Millisecond)(t)
+		}
+		t.Run("internal", func(it *testing.T) {
+			pt.Parallel(it, pt.With(pt.Group("outer",
+				pt.Test("", all.test(300*time.Millisecond)),
+				pt.Test("", all.test(300*time.Millisecond)),
+				pt.With(pt.Group("inner",
+					pt.Test("", innerTest),
+					pt.Test("", innerTest),
+					pt.Test("", innerTest),
+				), pt.Limit(1)),
+			), pt.Limit(2)))
+		})
+		if peak := all.max(); peak > 2 {
+			t.Errorf("max running tests of outer group %d > 2", peak)
+		}
+		if peak := inner.max(); peak != 1 {
+			t.Errorf("max running tests of inner group %d != 1", peak)
+		}
+	})
+	for _, parallel := range []int{1, 2} {
+		parallel := parallel
+		t.Run(fmt.Sprintf("should not deadlock with -parallel=%d", parallel), func(t *testing.T) {
+			t.Parallel()
+			output, err := runTestdata(t, "limit", fmt.Sprintf("GOFLAGS=-parallel=%d -timeout=1m", parallel))
+			if err != nil {
+				t.Fatalf("go test failed: %v\n%s", err, output)
+			}
+			assertContains(t, output,
+				"--- PASS: TestNestedGroups/outer/g3/b ",
+				"--- PASS: TestNestedParallel/limited/outer/b ",
+			)
+		})
+	}
+}
+
+// concurrencyCounter tracks max number of tests running at the same time.
+type concurrencyCounter struct {
+	running int32
+	peak    int32
+}
+
+func (c *concurrencyCounter) test(duration time.Duration) func(t *testing.T) {
+	return func(*testing.T) {
+		leave := c.enter()
+		time.Sleep(duration)
+		leave()
+	}
+}
+
+// enter marks a test as running, the returned function marks it as finished.
+func (c *concurrencyCounter) enter() func() {
+	running := atomic.AddInt32(&c.running, 1)
+	for {
+		peak := atomic.LoadInt32(&c.peak)
+		if running <= peak || atomic.CompareAndSwapInt32(&c.peak, peak, running) {
+			break
+		}
+	}
+	return func() { atomic.AddInt32(&c.running, -1) }
+}
+
+func (c *concurrencyCounter) max() int32 {
+	return atomic.LoadInt32(&c.peak)
+}
+
+// rendezvous fails tests which do not run at the same time.
+// The first n tests wait for each other, the rest tests pass through.
+type rendezvous struct {
+	mu      sync.Mutex
+	waiting int
+	all     chan struct{}
+}
+
+func newRendezvous(n int) *rendezvous {
+	return &rendezvous{
+		waiting: n,
+		all:     make(chan struct{}),
+	}
+}
+
+func (r *rendezvous) test(t *testing.T) {
+	r.mu.Lock()
+	r.waiting--
+	if r.waiting == 0 {
+		close(r.all)
+	}
+	r.mu.Unlock()
+	select {
+	case <-r.all:
+	case <-time.After(10 * time.Second):
+		t.Error("tests do not run at the same time")
+	}
+}
@@ -0,0 +1,147 @@
+package pt
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// listEnv is the environment variable which enables list mode: text or json.
+//
+// In list mode [Parallel], [PackageParallel] and [Sequential] start every subtest with its full go test name,
+// but do not execute bodies of tests and hooks. The tree of started subtests is printed to stdout instead.
+// Names are produced by go test itself, so they are sanitized and deduplicated (e.g. "#01" suffix) exactly as in a real run.
+// Flag -run of go test is applied as usual.
+// Iterations of [Repeat] and PT_STRESS are listed as well.
+//
+//	PT_LIST=json go test -run TestUser -v ./...
+//
+// In text mode every test is printed on a separate line with full name indented by its depth.
+// In json mode the tree of every [Parallel] call is printed as a single line json object.
+// Note that go test prints output of passed test binaries only in local directory mode or with -v flag.
+const listEnv = "PT_LIST"
+
+// listMode holds parsed value of PT_LIST environment variable.
+var listMode struct { //nolint:gochecknoglobals // environment is parsed once
+	once   sync.Once
+	format string // empty if list mode is disabled
+}
+
+// listFormat returns the format of list mode or empty string if it is disabled.
+func listFormat() string {
+	listMode.once.Do(func() {
+		switch value := os.Getenv(listEnv); value {
+		case "", "text", "json":
+			listMode.format = value
+		default:
+			panic(fmt.Sprintf("invalid %s value %q: expected text or json", listEnv, value))
+		}
+	})
+	return listMode.format
+}
+
+// listedTest is a subtest started in list mode.
+type listedTest struct {
+	Name     string        `json:"name"`
+	Kind     string        `json:"kind"`
+	File     string        `json:"file,omitempty"`
+	Line     int           `json:"line,omitempty"`
+	Tags     []string      `json:"tags,omitempty"`
+	Pending  bool          `json:"pending,omitempty"`
+	Focused  bool          `json:"focused,omitempty"`
+	Children []*listedTest `json:"children,omitempty"`
+}
+
+// list starts tests as subtests of t without executing their bodies and prints the tree.
+func list(t *testing.T, owner *node, tests []testing.InternalTest) {
+	root := &listedTest{
+		Name:     t.Name(),
+		Kind:     KindGroup.String(),
+		Children: listTests(t, owner, tests),
+	}
+	var buf bytes.Buffer
+	if listFormat() == "json" {
+		_ = json.NewEncoder(&buf).Encode(root) // can't fail
+	} else {
+		root.print(&buf, 0)
+	}
+	_, _ = os.Stdout.Write(buf.Bytes())
+}
+
+// listTests starts tests of owner as subtests of t without executing their bodies.
+func listTests(t *testing.T, owner *node, tests []testing.InternalTest) []*listedTest {
+	_, children := newScope(t, owner, tests)
+	var result []*listedTest
+	for _, test := range children {
+		n := lookup(test)
+		t.Run(test.Name, func(t *testing.T) {
+			item := newListedTest(t, n)
+			if count := stressCount(t.Name()); count > 0 {
+				item.Children = listIterations(t, n, count)
+			} else {
+				item.Children = listContent(t, n)
+			}
+			result = append(result, item)
+		})
+	}
+	return result
+}
+
+// listContent starts subtests which n (nil if test is not built by pt) would start as t.
+func listContent(t *testing.T, n *node) []*listedTest {
+	if n == nil {
+		return nil
+	}
+	switch n.kind {
+	case KindGroup, KindSerial, KindCases:
+		return listTests(t, n, n.children)
+	case KindRepeat:
+		return listIterations(t, lookup(n.children[0]), n.repeat)
+	}
+	return nil
+}
+
+// listIterations starts count iterations of n (nil if test is not built by pt) like [scope.repeat] does.
+func listIterations(t *testing.T, n *node, count int) []*listedTest {
+	var result []*listedTest
+	for i := 1; i <= count; i++ {
+		t.Run(fmt.Sprintf("iteration %d", i), func(t *testing.T) {
+			item := newListedTest(t, n)
+			item.Kind = KindTest.String()
+			if n != nil && n.kind != KindTest {
+				item.Kind = KindGroup.String()
+			}
+			item.Children = listContent(t, n)
+			result = append(result, item)
+		})
+	}
+	return result
+}
+
+func newListedTest(t *testing.T, n *node) *listedTest {
+	node := Node{n: n}
+	pos := node.Position()
+	options := node.Options()
+	return &listedTest{
+		Name:    t.Name(),
+		Kind:    node.Kind().String(),
+		File:    pos.Filename,
+		Line:    pos.Line,
+		Tags:    options.Tags,
+		Pending: options.Pending,
+		Focused: options.Focused,
+	}
+}
+
+func (l *listedTest) print(buf *bytes.Buffer, depth int) {
+	buf.WriteString(strings.Repeat("  ", depth))
+	buf.WriteString(l.Name)
+	buf.WriteString("\n")
+	for _, child := range l.Children {
+		child.print(buf, depth+1)
+	}
+}
@@ -0,0 +1,70 @@
+package pt_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestList(t *testing.T) {
+	t.Parallel()
+	t.Run("should print tree as text", func(t *testing.T) {
+		t.Parallel()
+		output, err := runTestdata(t, "list", "PT_LIST=text", "PT_STRESS=")
+		if err != nil {
+			t.Fatalf("go test failed: %s\n%s", err, output)
+		}
+		assertContains(t, output, strings.Join([]string{
+			"TestList",
+			"  TestList/group",
+			"    TestList/group/same_name",
+			"    TestList/group/same_name#01",
+			"    TestList/group/0",
+			"    TestList/group/1",
+			"  TestList/repeated",
+			"    TestList/repeated/iteration_1",
+			"    TestList/repeated/iteration_2",
+			"  TestList/raw",
+		}, "\n"))
+		assertNotContains(t, output, "is executed")
+	})
+	t.Run("should print tree as json", func(t *testing.T) {
+		t.Parallel()
+		output, err := runTestdata(t, "list", "PT_LIST=json", "PT_STRESS=TestList/raw:2")
+		if err != nil {
+			t.Fatalf("go test failed: %s\n%s", err, output)
+		}
+		type listed struct {
+			Name     string    `json:"name"`
+			Kind     string    `json:"kind"`
+			Line     int       `json:"line"`
+			Tags     []string  `json:"tags"`
+			Children []*listed `json:"children"`
+		}
+		var root listed
+		for _, line := range strings.Split(output, "\n") {
+			if strings.HasPrefix(line, "{") {
+				if err := json.Unmarshal([]byte(line), &root); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+		if len(root.Children) != 3 {
+			t.Fatalf("wrong tree: %s", output)
+		}
+		group := root.Children[0]
+		if group.Name != "TestList/group" || group.Kind != "Group" || group.Line != 12 || len(group.Children) != 4 {
+			t.Errorf("wrong group: %+v", group)
+		}
+		if test := group.Children[1]; test.Name != "TestList/group/same_name#01" || test.Kind != "Test" || strings.Join(test.Tags, ",") != "slow" {
+			t.Errorf("wrong test: %+v", test)
+		}
+		if repeated := root.Children[1]; repeated.Kind != "Repeat" || len(repeated.Children) != 2 {
+			t.Errorf("wrong repeated test: %+v", repeated)
+		}
+		if raw := root.Children[2]; len(raw.Children) != 2 || raw.Children[1].Name != "TestList/raw/iteration_2" {
+			t.Errorf("wrong stressed test: %+v", raw)
+		}
+		assertNotContains(t, output, "is executed")
+	})
+}
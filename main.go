@@ -0,0 +1,95 @@
+package pt
+
+import (
+	"fmt"
+	"os"
+	"testing"
+)
+
+/*
+Main runs tests, retries failed tests (see [Retry]) and prints reports collected by pt when all tests are finished.
+It returns exit code of [testing.M.Run] or zero if all failed tests passed on retry.
+It is designed to be called from TestMain:
+
+	func TestMain(m *testing.M) {
+		os.Exit(pt.Main(m))
+	}
+
+Main prints the list of flaky tests (see [Retry]) and log lines of the first failed iteration of repeated tests (see [Repeat]).
+
+If environment variable PT_REPORT is set, Main writes JSON report to the path it contains.
+The report contains the tree of tests run by pt with status, start and end time, number of attempts,
+tags and log lines of every test.
+
+If environment variable PT_JUNIT is set, Main writes JUnit XML report to the path it contains.
+Top level tests and groups are testsuites named by full test name, tests are testcases of the closest group.
+Failure messages, skip reasons, flaky tests (see [Retry]) and tags are reported as well.
+
+If environment variable PT_TRACE is set, Main writes Chrome Trace Event JSON to the path it contains.
+It shows when every test and group was running, one lane per concurrently running test.
+The trace can be opened in chrome://tracing or https://ui.perfetto.dev.
+
+If environment variable PT_SLOWEST is set to a number N, Main prints N slowest tests
+and the critical path: the chain of nested groups and tests which bounds the total runtime.
+All tests of [Serial] groups are on the critical path, while only the test finished last is on it for other groups.
+
+If environment variable PT_HISTORY is set, Main records durations of executed tests to the file it contains.
+On the next run pt reads the file to start the longest tests of every parallel group first
+and to balance tests between shards (see PT_SHARD in README). Sharded runs read the file, but don't update it.
+
+	PT_REPORT=report.json PT_JUNIT=junit.xml PT_TRACE=trace.json PT_SLOWEST=10 PT_HISTORY=.pt-history.json go test -v ./...
+
+Relative paths are resolved against the directory of the package, because go test runs the test binary there.
+Log lines are captured from the output of go test, so logs of passed tests are captured only with -v flag.
+Reports are written atomically when all tests are finished, they are not written if the test binary crashes.
+*/
+func Main(m *testing.M) int {
+	if m == nil {
+		panic("argument m *testing.M can not be nil")
+	}
+	reportFile, junitFile, traceFile := reportSetting(reportEnv), reportSetting(junitEnv), reportSetting(traceEnv)
+	historyFile, slowest := historySetting(), slowestCount()
+	if reportFile != "" || junitFile != "" || traceFile != "" || historyFile != "" || slowest > 0 {
+		reports.enable()
+	}
+	restore := func() {}
+	if os.Getenv(attemptEnv) == "" { // failed tests and logs are captured to be retried and reported
+		var err error
+		if restore, err = reports.capture(); err != nil {
+			restore = func() {}
+			fmt.Fprintf(os.Stderr, "pt: failed to capture output of tests: %v\n", err)
+		}
+	}
+	code := m.Run()
+	restore()
+	code = retries.run(os.Stdout, code, reports.failures())
+	flakes.print(os.Stdout)
+	repeats.print(os.Stdout, reports.logsOf)
+	if slowest > 0 {
+		reports.printSummary(os.Stdout, slowest)
+	}
+	if reportFile != "" {
+		code = writeReport(reportFile, reports.write, code)
+	}
+	if junitFile != "" {
+		code = writeReport(junitFile, reports.writeJUnit, code)
+	}
+	if traceFile != "" {
+		code = writeReport(traceFile, reports.writeTrace, code)
+	}
+	if historyFile != "" {
+		code = writeReport(historyFile, reports.writeHistory, code)
+	}
+	return code
+}
+
+// writeReport writes the report to path, it returns non-zero exit code if writing fails.
+func writeReport(path string, write func(path string) error, code int) int {
+	if err := write(path); err != nil {
+		fmt.Fprintf(os.Stderr, "pt: failed to write report %s: %v\n", path, err)
+		if code == 0 {
+			return 1
+		}
+	}
+	return code
+}
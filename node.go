@@ -0,0 +1,145 @@
+package pt
+
+import (
+	"go/token"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unsafe"
+)
+
+// Kind describes what a [Node] represents.
+type Kind int
+
+const (
+	KindTest    Kind = iota // test built by [Test] or arbitrary [testing.InternalTest]
+	KindGroup               // group built by [Group]
+	KindSerial              // group built by [Serial]
+	KindHook                // hook built by [BeforeAll], [AfterAll], [BeforeEach] or [AfterEach]
+	KindProvide             // directive built by [Provide]
+	KindCases               // table-driven tests built by [Each] or [Table]
+	KindRepeat              // repeated test built by [Repeat]
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindTest:
+		return "Test"
+	case KindGroup:
+		return "Group"
+	case KindSerial:
+		return "Serial"
+	case KindHook:
+		return "Hook"
+	case KindProvide:
+		return "Provide"
+	case KindCases:
+		return "Cases"
+	case KindRepeat:
+		return "Repeat"
+	}
+	return "unknown"
+}
+
+// node is a description of [testing.InternalTest] built by pt.
+// It allows to find out the structure of the tree before running it.
+type node struct {
+	name        string
+	kind        Kind
+	pos         token.Position      // where the node is declared
+	f           func(t *testing.T)  // test body for KindTest, hook body for KindHook
+	tb          func(tb testing.TB) // test body for KindTest built by TB, nil for other tests
+	children    []testing.InternalTest
+	hook        hookKind
+	fixture     any               // *Fixture[T] for KindProvide
+	limit       int               // max number of children running at the same time, 0 means no limit
+	resources   claims            // resources claimed by the test or by each test of the group
+	timeout     time.Duration     // timeout of the test or of each test of the group
+	retry       *retryPolicy      // retry policy of the test or of each test of the group
+	repeat      int               // number of iterations for KindRepeat
+	cases       []any             // values of cases for KindCases in order of children
+	tags        []string          // tags of the test or of each test of the group
+	shuffle     bool              // tests of the group and nested groups are started in random order
+	runParallel bool              // tests are benchmarked with b.RunParallel
+	detectLeaks bool              // the test or each test of the group fails if its goroutines are running after it
+	env         map[string]string // environment variables of the test or of each test of the group
+	focused     bool
+	pending     bool
+}
+
+// nodes maps identity of [testing.InternalTest.F] to the node that created it.
+var nodes sync.Map //nolint:gochecknoglobals // registry is shared by all tests in package
+
+// register returns [testing.InternalTest] for the node n and remembers it,
+// so that [lookup] can find n by the returned value later.
+func register(n *node) testing.InternalTest {
+	if !n.pos.IsValid() {
+		n.pos = callerPosition()
+	}
+	test := testing.InternalTest{
+		Name: n.name,
+		F:    n.run,
+	}
+	nodes.Store(funcID(test.F), n)
+	return test
+}
+
+// callerPosition returns the position of the closest caller outside of pt.
+func callerPosition() token.Position {
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, packagePrefix) {
+			return token.Position{Filename: frame.File, Line: frame.Line}
+		}
+		if !more {
+			return token.Position{}
+		}
+	}
+}
+
+// packagePrefix is the prefix of names of all functions in pt.
+var packagePrefix = reflect.TypeOf(node{}).PkgPath() + "." //nolint:gochecknoglobals // constant computed once
+
+// lookup returns the node which was used to build test.
+// It returns nil if test is not built by pt.
+func lookup(test testing.InternalTest) *node {
+	if test.F == nil {
+		return nil
+	}
+	value, ok := nodes.Load(funcID(test.F))
+	if !ok {
+		return nil
+	}
+	n, _ := value.(*node)
+	return n
+}
+
+// funcID returns identity of the closure f.
+// Functions are not comparable in go, but a func value is a pointer to the closure object,
+// which is unique for every evaluation of the method value [node.run].
+func funcID(f func(t *testing.T)) unsafe.Pointer {
+	return *(*unsafe.Pointer)(unsafe.Pointer(&f)) //nolint:gosec // func value is a pointer to closure
+}
+
+// run is [testing.InternalTest.F] of the node.
+func (n *node) run(t *testing.T) {
+	switch n.kind {
+	case KindTest:
+		n.f(t)
+	case KindGroup, KindCases:
+		run(t, n, n.children, true)
+	case KindSerial:
+		run(t, n, n.children, false)
+	case KindRepeat:
+		scopeOf(t).repeat(t, lookup(n.children[0]), n.children[0], n.repeat)
+	case KindHook:
+		t.Fatalf("hook %s can be used only as an argument of Group, Serial, Parallel, Sequential and PackageParallel", n.hook)
+	case KindProvide:
+		t.Fatal("Provide can be used only as an argument of Group, Serial, Parallel, Sequential and PackageParallel")
+	}
+}
@@ -0,0 +1,39 @@
+package pt
+
+import (
+	"testing"
+)
+
+// Option configures [testing.InternalTest] built by pt.
+// Options are passed to [Test] or applied to any test or group with [With].
+type Option func(n *node)
+
+/*
+With returns a copy of test configured with provided options.
+The test can be built by [Test], [Group], [Serial] or be an arbitrary [testing.InternalTest].
+It panics for tests built by [Each] and [Table], because they are added directly to the enclosing group.
+
+	pt.With(pt.Group("calls stub server", tests...), pt.Limit(10))
+*/
+func With(test testing.InternalTest, opts ...Option) testing.InternalTest {
+	var n node
+	if original := lookup(test); original != nil {
+		if original.kind == KindCases {
+			panic("options can not be applied to Each and Table, apply them to the enclosing group")
+		}
+		n = *original
+	} else {
+		if test.F == nil {
+			panic("argument test testing.InternalTest must have F")
+		}
+		n = node{
+			name: test.Name,
+			kind: KindTest,
+			f:    test.F,
+		}
+	}
+	for _, opt := range opts {
+		opt(&n)
+	}
+	return register(&n)
+}
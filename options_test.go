@@ -0,0 +1,62 @@
+package pt_test
+
+import (
+	"testing"
+
+	"github.com/maratori/pt"
+)
+
+func TestWith(t *testing.T) {
+	t.Parallel()
+	t.Run("should panic on test without F", func(t *testing.T) {
+		t.Parallel()
+		defer assertPanic(t, "argument test testing.InternalTest must have F")
+		pt.With(testing.InternalTest{})
+	})
+	t.Run("should keep name", func(t *testing.T) {
+		t.Parallel()
+		for _, test := range []testing.InternalTest{
+			pt.Test("abc", func(*testing.T) {}),
+			pt.Group("abc"),
+			pt.Serial("abc"),
+			{Name: "abc", F: func(*testing.T) {}},
+		} {
+			if pt.With(test).Name != "abc" {
+				t.Error("name is wrong")
+			}
+		}
+	})
+	t.Run("should run wrapped test", func(t *testing.T) {
+		t.Parallel()
+		called := false
+		internalTest := pt.With(testing.InternalTest{F: func(*testing.T) {
+			called = true
+		}})
+		internalTest.F(nil)
+		if !called {
+			t.Error("test is not called")
+		}
+	})
+	t.Run("should run wrapped group", func(t *testing.T) {
+		t.Parallel()
+		called := false
+		internalTest := pt.With(pt.Group("", pt.Test("", func(*testing.T) {
+			called = true
+		})))
+		t.Run("internal", func(it *testing.T) {
+			internalTest.F(it)
+		})
+		if !called {
+			t.Error("test is not called")
+		}
+	})
+	t.Run("should not change original test", func(t *testing.T) {
+		t.Parallel()
+		meeting := newRendezvous(2)
+		group := pt.Group("", pt.Test("", meeting.test), pt.Test("", meeting.test))
+		pt.With(group, pt.Limit(1))
+		t.Run("internal", func(it *testing.T) {
+			group.F(it)
+		})
+	})
+}
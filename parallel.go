@@ -0,0 +1,40 @@
+package pt
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// parallelCalledTwice is the prefix of the panic message of [testing.T.Parallel] called for a parallel test.
+// The message is the same since go 1.7.
+const parallelCalledTwice = "testing: t.Parallel called multiple times"
+
+// parallelTests contains tests switched to parallel mode by pt.
+var parallelTests sync.Map //nolint:gochecknoglobals // set of *testing.T, entries are removed on cleanup
+
+// setParallel calls t.Parallel() and remembers that t is parallel.
+func setParallel(t *testing.T) {
+	t.Parallel()
+	parallelTests.Store(t, struct{}{})
+	t.Cleanup(func() {
+		parallelTests.Delete(t)
+	})
+}
+
+// ensureParallel switches t to parallel mode unless it is already parallel.
+// Tests switched by pt are known, otherwise t.Parallel() is called and its panic
+// is recovered if t has been already switched by user. Other panics (e.g. after t.Setenv) are propagated.
+// It doesn't depend on private fields of [testing.T], so it works with any go version.
+func ensureParallel(t *testing.T) {
+	if _, ok := parallelTests.Load(t); ok {
+		return
+	}
+	defer func() {
+		if r := recover(); r != nil && !strings.HasPrefix(fmt.Sprint(r), parallelCalledTwice) {
+			panic(r)
+		}
+	}()
+	setParallel(t)
+}
@@ -48,6 +48,31 @@ You can achieve the same behavior with bare testing package:
 		})
 	}
 
+# Hooks
+
+Hooks are passed along with tests to [Group], [Parallel] and [PackageParallel].
+[BeforeAll] and [AfterAll] run once per group, [BeforeEach] and [AfterEach] run around each test in the group
+including tests of nested groups.
+
+	func TestRepo(t *testing.T) {
+		pt.PackageParallel(t,
+			pt.BeforeAll(func(t *testing.T) {
+				// start database
+			}),
+			pt.AfterAll(func(t *testing.T) {
+				// stop database
+			}),
+			pt.Group("users",
+				pt.BeforeEach(func(t *testing.T) {
+					// insert user
+				}),
+				pt.Test("should find user", func(t *testing.T) {
+					// test code
+				}),
+			),
+		)
+	}
+
 # Parallel vs PackageParallel
 
 The difference can be demonstrated with the code below. Tests will be executed in the following sequence:
@@ -78,13 +103,12 @@ Code:
 package pt
 
 import (
-	"reflect"
 	"testing"
 )
 
 /*
 PackageParallel is non-blocking function that runs provided tests in parallel with other tests in package.
-It can take [Group] and [Test] as arguments.
+It can take [Group], [Test] and hooks ([BeforeAll], [AfterAll], [BeforeEach], [AfterEach]) as arguments.
 
 	func TestA(t *testing.T) {
 		pt.PackageParallel(t, test1, test2)
@@ -110,15 +134,13 @@ func PackageParallel(t *testing.T, tests ...testing.InternalTest) {
 	if t == nil {
 		panic("argument t *testing.T can not be nil")
 	}
-	if !alreadyParallel(t) { // avoid panic
-		t.Parallel()
-	}
+	ensureParallel(t)
 	Parallel(t, tests...)
 }
 
 /*
 Parallel is non-blocking function that runs provided tests in parallel.
-It can take [Group] and [Test] as arguments.
+It can take [Group], [Test] and hooks ([BeforeAll], [AfterAll], [BeforeEach], [AfterEach]) as arguments.
 
 	func TestA(t *testing.T) {
 		pt.Parallel(t, test1, test2)
@@ -153,44 +175,81 @@ func Parallel(t *testing.T, tests ...testing.InternalTest) {
 	if t == nil {
 		panic("argument t *testing.T can not be nil")
 	}
-	for _, test := range tests {
-		test := test
-		t.Run(test.Name, func(t *testing.T) {
-			t.Parallel()
-			test.F(t)
-		})
+	run(t, nil, tests, true)
+}
+
+/*
+Sequential is blocking function that runs provided tests one by one in declaration order.
+It can take the same arguments as [Parallel].
+Nested groups still run their tests in parallel.
+
+	func TestA(t *testing.T) {
+		pt.Sequential(t, test1, test2)
+	}
+
+is equivalent to
+
+	func TestA(t *testing.T) {
+		t.Run(test1.Name, test1.F)
+		t.Run(test2.Name, test2.F)
+	}
+*/
+func Sequential(t *testing.T, tests ...testing.InternalTest) {
+	if t == nil {
+		panic("argument t *testing.T can not be nil")
 	}
+	run(t, nil, tests, false)
 }
 
 // Group is a constructor of [testing.InternalTest].
 // It wraps provided tests with a single [testing.InternalTest].
 // Provided tests will run in parallel when the wrapper is executed.
-// It is designed to be an argument of [Group], [Parallel] and [PackageParallel].
+// Hooks provided among tests are applied to the group (see [BeforeAll], [AfterAll], [BeforeEach], [AfterEach]).
+// It is designed to be an argument of [Group], [Serial], [Parallel], [Sequential] and [PackageParallel].
 func Group(name string, tests ...testing.InternalTest) testing.InternalTest {
-	return testing.InternalTest{
-		Name: name,
-		F: func(t *testing.T) {
-			Parallel(t, tests...)
-		},
-	}
+	return register(&node{
+		name:     name,
+		kind:     KindGroup,
+		children: tests,
+	})
+}
+
+// Serial is a constructor of [testing.InternalTest].
+// It wraps provided tests with a single [testing.InternalTest] like [Group] does,
+// but provided tests will run one by one in declaration order when the wrapper is executed.
+// The wrapper itself runs in parallel with its siblings.
+// It is designed to be an argument of [Group], [Serial], [Parallel], [Sequential] and [PackageParallel].
+//
+//	pt.Group("user",
+//		pt.Serial("lifecycle",
+//			pt.Test("should be created", testCreate),
+//			pt.Test("should be updated", testUpdate),
+//			pt.Test("should be deleted", testDelete),
+//		),
+//		pt.Test("should not be found", testNotFound), // runs in parallel with "lifecycle"
+//	)
+func Serial(name string, tests ...testing.InternalTest) testing.InternalTest {
+	return register(&node{
+		name:     name,
+		kind:     KindSerial,
+		children: tests,
+	})
 }
 
 // Test is a simple constructor of [testing.InternalTest].
-// It is designed to be an argument of [Group], [Parallel] and [PackageParallel].
-func Test(name string, test func(t *testing.T)) testing.InternalTest {
+// It is designed to be an argument of [Group], [Serial], [Parallel], [Sequential] and [PackageParallel].
+// Options can be provided to configure the test (see [Option]).
+func Test(name string, test func(t *testing.T), opts ...Option) testing.InternalTest {
 	if test == nil {
 		panic("argument test func(t *testing.T) can not be nil")
 	}
-	return testing.InternalTest{
-		Name: name,
-		F:    test,
+	n := &node{
+		name: name,
+		kind: KindTest,
+		f:    test,
 	}
-}
-
-// alreadyParallel returns value of private field isParallel for provided t [*testing.T].
-func alreadyParallel(t *testing.T) bool {
-	testObject := reflect.ValueOf(t).Elem()
-	isParallelField := testObject.FieldByName("isParallel")
-	isParallel := isParallelField.Bool()
-	return isParallel
+	for _, opt := range opts {
+		opt(n)
+	}
+	return register(n)
 }
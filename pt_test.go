@@ -1,6 +1,9 @@
 package pt_test
 
 import (
+	"os"
+	"os/exec"
+	"strings"
 	"testing"
 	"time"
 
@@ -34,6 +37,27 @@ func TestPackageParallel(t *testing.T) {
 		pt.PackageParallel(t, testing.InternalTest{F: func(*testing.T) {}})
 		pt.PackageParallel(t, testing.InternalTest{F: func(*testing.T) {}})
 	})
+	t.Run("should not panic if t.Parallel is called before", func(t *testing.T) {
+		t.Parallel()
+		t.Run("internal", func(it *testing.T) {
+			it.Parallel()
+			pt.PackageParallel(it, testing.InternalTest{F: func(*testing.T) {}})
+		})
+	})
+	t.Run("should not panic inside parallel test", func(t *testing.T) {
+		t.Parallel()
+		called := false
+		t.Run("internal", func(it *testing.T) {
+			pt.Parallel(it, pt.Test("test", func(t *testing.T) {
+				pt.PackageParallel(t, testing.InternalTest{F: func(*testing.T) {
+					called = true
+				}})
+			}))
+		})
+		if !called {
+			t.Error("test is not called")
+		}
+	})
 	t.Run("should run 1 test", func(t *testing.T) {
 		t.Parallel()
 		called := false
@@ -431,6 +455,128 @@ func TestGroup2(t *testing.T) {
 	})
 }
 
+func TestSequential(t *testing.T) {
+	t.Parallel()
+	t.Run("should panic on nil T", func(t *testing.T) {
+		t.Parallel()
+		defer assertPanic(t, "argument t *testing.T can not be nil")
+		pt.Sequential(nil)
+	})
+	t.Run("should not panic without tests", func(t *testing.T) {
+		t.Parallel()
+		pt.Sequential(&testing.T{})
+	})
+	t.Run("should run tests in order", func(t *testing.T) {
+		t.Parallel()
+		var events []string
+		pt.Sequential(t,
+			pt.Test("test1", func(*testing.T) { events = append(events, "test1") }),
+			pt.Test("test2", func(*testing.T) { events = append(events, "test2") }),
+			testing.InternalTest{F: func(*testing.T) { events = append(events, "test3") }},
+		)
+		assertEvents(t, events, "test1", "test2", "test3")
+	})
+	t.Run("should be blocking", func(t *testing.T) {
+		t.Parallel()
+		called := false
+		pt.Sequential(t, testing.InternalTest{F: func(*testing.T) {
+			called = true
+		}})
+		if !called {
+			t.Error("test is not called")
+		}
+	})
+}
+
+func TestSerial(t *testing.T) {
+	t.Parallel()
+	t.Run("should return right name", func(t *testing.T) {
+		t.Parallel()
+		internalTest := pt.Serial("abc")
+		if internalTest.Name != "abc" {
+			t.Error("name is wrong")
+		}
+	})
+	t.Run("should not panic without tests", func(t *testing.T) {
+		t.Parallel()
+		internalTest := pt.Serial("")
+		internalTest.F(&testing.T{})
+	})
+	t.Run("should run tests in order", func(t *testing.T) {
+		t.Parallel()
+		var events []string
+		t.Run("internal", func(it *testing.T) {
+			pt.Parallel(it, pt.Serial("serial",
+				pt.Test("test1", func(*testing.T) { events = append(events, "test1") }),
+				pt.Test("test2", func(*testing.T) { events = append(events, "test2") }),
+				pt.Test("test3", func(*testing.T) { events = append(events, "test3") }),
+			))
+		})
+		assertEvents(t, events, "test1", "test2", "test3")
+	})
+}
+
+func TestSerial2(t *testing.T) {
+	// Do not call t.Parallel() because test measures execution time
+	t.Run("should run tests sequential", func(t *testing.T) {
+		singleTestDuration := 1 * time.Second
+		expectedMinDuration := 2 * singleTestDuration
+		expectedMaxDuration := 2*singleTestDuration + 300*time.Millisecond
+		internalTest := pt.Serial("",
+			testing.InternalTest{F: func(*testing.T) {
+				time.Sleep(singleTestDuration)
+			}},
+			testing.InternalTest{F: func(*testing.T) {
+				time.Sleep(singleTestDuration)
+			}},
+		)
+		start := time.Now()
+		t.Run("internal", func(it *testing.T) {
+			internalTest.F(it)
+		})
+		elapsed := time.Since(start)
+		if elapsed < expectedMinDuration {
+			t.Errorf("tests execution time %s not exceeded %s", elapsed, expectedMinDuration)
+		}
+		if elapsed > expectedMaxDuration {
+			t.Errorf("tests execution time %s exceeded %s", elapsed, expectedMaxDuration)
+		}
+	})
+	t.Run("should run in parallel with siblings", func(t *testing.T) {
+		singleTestDuration := 1 * time.Second
+		expectedMinDuration := 2 * singleTestDuration
+		expectedMaxDuration := 2*singleTestDuration + 300*time.Millisecond
+		start := time.Now()
+		t.Run("internal", func(it *testing.T) {
+			pt.Parallel(it,
+				pt.Serial("",
+					testing.InternalTest{F: func(*testing.T) {
+						time.Sleep(singleTestDuration)
+					}},
+					pt.Group("",
+						testing.InternalTest{F: func(*testing.T) {
+							time.Sleep(singleTestDuration)
+						}},
+						testing.InternalTest{F: func(*testing.T) {
+							time.Sleep(singleTestDuration)
+						}},
+					),
+				),
+				testing.InternalTest{F: func(*testing.T) {
+					time.Sleep(2 * singleTestDuration)
+				}},
+			)
+		})
+		elapsed := time.Since(start)
+		if elapsed < expectedMinDuration {
+			t.Errorf("tests execution time %s not exceeded %s", elapsed, expectedMinDuration)
+		}
+		if elapsed > expectedMaxDuration {
+			t.Errorf("tests execution time %s exceeded %s", elapsed, expectedMaxDuration)
+		}
+	})
+}
+
 func TestTest(t *testing.T) {
 	t.Parallel()
 	t.Run("should panic on nil", func(t *testing.T) {
@@ -469,3 +615,44 @@ func assertPanic(t *testing.T, expected string) {
 		t.Errorf("unexpected panic value: %v", value)
 	}
 }
+
+// runTestdata runs go test for the package in testdata directory and returns its output.
+// Environment variables are passed in form "KEY=value", "KEY=" removes the variable.
+func runTestdata(t *testing.T, pkg string, env ...string) (string, error) {
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go is not found")
+	}
+	cmd := exec.Command(goBin, "test", "-v", "-count=1", "./testdata/"+pkg)
+	cmd.Env = os.Environ()
+	for _, variable := range env {
+		key := variable[:strings.Index(variable, "=")+1]
+		for i := 0; i < len(cmd.Env); i++ {
+			if strings.HasPrefix(cmd.Env[i], key) {
+				cmd.Env = append(cmd.Env[:i], cmd.Env[i+1:]...)
+				i--
+			}
+		}
+		if !strings.HasSuffix(variable, "=") {
+			cmd.Env = append(cmd.Env, variable)
+		}
+	}
+	output, err := cmd.CombinedOutput()
+	return string(output), err
+}
+
+func assertContains(t *testing.T, output string, expected ...string) {
+	for _, s := range expected {
+		if !strings.Contains(output, s) {
+			t.Errorf("output does not contain %q:\n%s", s, output)
+		}
+	}
+}
+
+func assertNotContains(t *testing.T, output string, unexpected ...string) {
+	for _, s := range unexpected {
+		if strings.Contains(output, s) {
+			t.Errorf("output contains %q:\n%s", s, output)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package pt
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// stressEnv is the environment variable which selects tests to repeat.
+// The format is comma separated list of full test names with repeat counts:
+//
+//	PT_STRESS=TestSum/should_be_sum_of_two_values:200,TestFibonacci:10
+const stressEnv = "PT_STRESS"
+
+/*
+Repeat is a constructor of [testing.InternalTest] which runs test count times concurrently.
+The test can be built by [Test], [Group], [Serial] or be an arbitrary [testing.InternalTest].
+Every iteration is a subtest named "iteration N" with all settings of the original test.
+When some iterations fail, the number of failed iterations and the name of the first failed one are logged.
+If tests are run by [Main], it prints log lines of the first failed iteration and its subtests when all tests are finished.
+It is designed to be an argument of [Group], [Serial], [Parallel], [Sequential] and [PackageParallel].
+
+	pt.Repeat(100, pt.Test("should not race", testRace))
+
+Any test run by pt can be repeated without changing the code by environment variable PT_STRESS.
+It contains comma separated list of full test names (as printed by go test -v) with repeat counts:
+
+	PT_STRESS=TestSum/should_be_sum_of_two_values:200 go test -run TestSum
+*/
+func Repeat(count int, test testing.InternalTest) testing.InternalTest {
+	if count < 1 {
+		panic("argument count must be positive")
+	}
+	if test.F == nil {
+		panic("argument test testing.InternalTest must have F")
+	}
+	return register(&node{
+		name:     test.Name,
+		kind:     KindRepeat,
+		children: []testing.InternalTest{test},
+		repeat:   count,
+	})
+}
+
+// repeat runs count iterations of test n (nil if test is not built by pt) as subtests of t.
+func (s *scope) repeat(t *testing.T, n *node, test testing.InternalTest, count int) {
+	var mu sync.Mutex
+	failed := 0
+	firstFailed := ""
+	t.Cleanup(func() { // subtests are finished before cleanup
+		mu.Lock()
+		defer mu.Unlock()
+		if failed > 0 {
+			t.Logf("%d of %d iterations failed, see output of the first failed iteration %s", failed, count, firstFailed)
+			repeats.add(failedRepeat{name: t.Name(), failed: failed, count: count, firstFailed: firstFailed})
+		}
+	})
+	parent := t
+	for i := 1; i <= count; i++ {
+		t.Run(fmt.Sprintf("iteration %d", i), func(t *testing.T) {
+			reports.startTest(t, parent, n)
+			setParallel(t)
+			reports.running(t)
+			t.Cleanup(func() {
+				if !t.Failed() {
+					return
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				if failed == 0 {
+					firstFailed = t.Name()
+				}
+				failed++
+			})
+			s.runChild(t, n, test)
+		})
+	}
+}
+
+// failedRepeat is a repeated test with failed iterations.
+type failedRepeat struct {
+	name        string
+	failed      int
+	count       int
+	firstFailed string // full name of the first failed iteration
+}
+
+type failedRepeats struct {
+	mu    sync.Mutex
+	tests []failedRepeat
+}
+
+var repeats failedRepeats //nolint:gochecknoglobals // failed iterations are reported at the end of the test binary
+
+func (f *failedRepeats) add(test failedRepeat) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.tests = append(f.tests, test)
+}
+
+// print writes log lines of the first failed iteration of every repeated test to w.
+// logs returns captured log lines of the test with provided full name and its subtests.
+func (f *failedRepeats) print(w io.Writer, logs func(name string) map[string][]string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	sort.Slice(f.tests, func(i, j int) bool {
+		return f.tests[i].name < f.tests[j].name
+	})
+	for _, test := range f.tests {
+		fmt.Fprintf(w, "\nREPEAT: %s %d of %d iterations failed, output of the first failed iteration %s:\n",
+			test.name, test.failed, test.count, test.firstFailed)
+		lines := logs(test.firstFailed)
+		names := make([]string, 0, len(lines))
+		for name := range lines {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Fprintf(w, "    --- %s\n", name)
+			for _, line := range lines[name] {
+				fmt.Fprintf(w, "        %s\n", line)
+			}
+		}
+	}
+}
+
+// stress holds parsed value of PT_STRESS environment variable.
+var stress struct { //nolint:gochecknoglobals // environment is parsed once
+	once   sync.Once
+	counts map[string]int
+}
+
+// stressCount returns the number of iterations for the test with provided full name or 0 if it is not repeated.
+func stressCount(name string) int {
+	stress.once.Do(func() {
+		stress.counts = parseStress(os.Getenv(stressEnv))
+	})
+	return stress.counts[name]
+}
+
+func parseStress(value string) map[string]int {
+	counts := make(map[string]int)
+	for _, item := range strings.Split(value, ",") {
+		if strings.TrimSpace(item) == "" {
+			continue
+		}
+		separator := strings.LastIndex(item, ":")
+		if separator < 0 {
+			panic(fmt.Sprintf("invalid %s item %q: expected name:count", stressEnv, item))
+		}
+		count, err := strconv.Atoi(item[separator+1:])
+		if err != nil || count < 1 {
+			panic(fmt.Sprintf("invalid %s item %q: count must be positive integer", stressEnv, item))
+		}
+		counts[strings.TrimSpace(item[:separator])] = count
+	}
+	return counts
+}
@@ -0,0 +1,91 @@
+package pt_test
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/maratori/pt"
+)
+
+func TestRepeat(t *testing.T) {
+	t.Parallel()
+	t.Run("should panic on non-positive count", func(t *testing.T) {
+		t.Parallel()
+		defer assertPanic(t, "argument count must be positive")
+		pt.Repeat(0, pt.Test("", func(*testing.T) {}))
+	})
+	t.Run("should panic on test without F", func(t *testing.T) {
+		t.Parallel()
+		defer assertPanic(t, "argument test testing.InternalTest must have F")
+		pt.Repeat(1, testing.InternalTest{})
+	})
+	t.Run("should return right name", func(t *testing.T) {
+		t.Parallel()
+		if pt.Repeat(1, pt.Test("abc", func(*testing.T) {})).Name != "abc" {
+			t.Error("name is wrong")
+		}
+	})
+	t.Run("should run all iterations", func(t *testing.T) {
+		t.Parallel()
+		var events eventLog
+		t.Run("internal", func(it *testing.T) {
+			pt.Parallel(it,
+				pt.BeforeEach(events.hook("before")),
+				pt.Repeat(3, pt.Test("test", func(t *testing.T) { events.add(t.Name()) })),
+			)
+		})
+		actual := events.get()
+		sort.Strings(actual)
+		prefix := t.Name() + "/internal/test/iteration_"
+		assertEvents(t, actual, prefix+"1", prefix+"2", prefix+"3", "before", "before", "before")
+	})
+	t.Run("should repeat group", func(t *testing.T) {
+		t.Parallel()
+		var events eventLog
+		t.Run("internal", func(it *testing.T) {
+			pt.Parallel(it,
+				pt.Repeat(2, pt.Group("group", pt.Test("test", func(t *testing.T) { events.add(t.Name()) }))),
+			)
+		})
+		actual := events.get()
+		sort.Strings(actual)
+		prefix := t.Name() + "/internal/group/iteration_"
+		assertEvents(t, actual, prefix+"1/test", prefix+"2/test")
+	})
+	t.Run("should repeat test selected by environment", func(t *testing.T) {
+		t.Parallel()
+		output, err := runTestdata(t, "repeat", "PT_STRESS=TestStress/group/stressed_test:5", "PT_TESTDATA_FAIL=")
+		if err != nil {
+			t.Fatalf("go test failed: %s\n%s", err, output)
+		}
+		assertContains(t, output,
+			"--- PASS: TestStress/group/stressed_test/iteration_1 ",
+			"--- PASS: TestStress/group/stressed_test/iteration_5 ",
+		)
+		assertNotContains(t, output,
+			"stressed_test/iteration_6",
+			"other_test/iteration_1",
+		)
+	})
+	t.Run("should report failed iterations", func(t *testing.T) {
+		t.Parallel()
+		output, err := runTestdata(t, "repeat", "PT_STRESS=", "PT_TESTDATA_FAIL=1")
+		if err == nil {
+			t.Fatalf("go test succeeded:\n%s", output)
+		}
+		assertContains(t, output,
+			"2 of 4 iterations failed, see output of the first failed iteration TestRepeatFails/flaky/iteration_",
+			"--- FAIL: TestRepeatFails/flaky/iteration_2 ",
+			"--- PASS: TestRepeatFails/flaky/iteration_3 ",
+			"REPEAT: TestRepeatFails/flaky 2 of 4 iterations failed, output of the first failed iteration TestRepeatFails/flaky/iteration_",
+		)
+		summary := output[strings.Index(output, "REPEAT: "):]
+		first := summary[strings.Index(summary, "iteration_")+len("iteration_")]
+		assertContains(t, summary,
+			"    --- TestRepeatFails/flaky/iteration_"+string(first)+"\n",
+			"        repeat_test.go:",
+			"iteration "+string(first)+" failed",
+		)
+	})
+}
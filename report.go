@@ -0,0 +1,323 @@
+package pt
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// reportEnv is the environment variable with the path of JSON report written by [Main].
+//
+// The report contains the tree of tests run by pt with status, start and end time, number of attempts (see [Retry]),
+// tags and log lines of every test. Log lines are captured from the output of go test,
+// so logs of passed tests are captured only with -v flag.
+// The report is written atomically when all tests are finished, it is not written if the test binary crashes.
+const reportEnv = "PT_REPORT"
+
+// reportedTest is a test in the JSON report.
+type reportedTest struct {
+	Name     string          `json:"name"`
+	Kind     string          `json:"kind"`
+	File     string          `json:"file,omitempty"`
+	Line     int             `json:"line,omitempty"`
+	Tags     []string        `json:"tags,omitempty"` // including tags of groups
+	Status   string          `json:"status"`
+	Start    time.Time       `json:"start"`
+	End      time.Time       `json:"end"`
+	Attempts int             `json:"attempts"`
+	Logs     []string        `json:"logs,omitempty"`
+	Children []*reportedTest `json:"children,omitempty"`
+
+	running time.Time // when the test started running after waiting for parallel slots and resources
+	unit    bool      // the test is sharded as a whole (see [isShardUnit])
+	inUnit  bool      // the test is a subtest of a test sharded as a whole
+}
+
+// report is the JSON report written by [Main].
+type report struct {
+	Start time.Time       `json:"start"`
+	End   time.Time       `json:"end"`
+	Tests []*reportedTest `json:"tests"`
+}
+
+// reporter collects tests run by pt for reports.
+type reporter struct {
+	mu      sync.Mutex
+	enabled bool
+	start   time.Time
+	roots   []*reportedTest
+	tests   map[*testing.T]*reportedTest
+	logs    map[string][]string // log lines by full test name
+	failed  []string            // full names of failed tests captured from the output of go test
+}
+
+var reports reporter //nolint:gochecknoglobals // reports are written at the end of the test binary
+
+// enable starts collecting tests.
+func (r *reporter) enable() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.enabled = true
+	r.start = time.Now()
+	r.tests = make(map[*testing.T]*reportedTest)
+}
+
+// startRoot adds t to the report as a root unless it is already reported.
+// The root is reported as [KindGroup] if its subtests run in parallel or as [KindSerial] otherwise.
+func (r *reporter) startRoot(t *testing.T, parallel bool) {
+	r.mu.Lock()
+	_, ok := r.tests[t]
+	r.mu.Unlock()
+	if !ok {
+		n := &node{kind: KindSerial}
+		if parallel {
+			n.kind = KindGroup
+		}
+		r.startTest(t, nil, n)
+	}
+}
+
+// startTest adds t which runs test n (nil if test is not built by pt) to the report as a child of parent.
+// The test is finished in cleanup, so startTest should be called before other cleanups are registered.
+func (r *reporter) startTest(t *testing.T, parent *testing.T, n *node) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.enabled {
+		return
+	}
+	node := Node{n: n}
+	pos := node.Position()
+	test := &reportedTest{
+		Name:     t.Name(),
+		Kind:     node.Kind().String(),
+		File:     pos.Filename,
+		Line:     pos.Line,
+		Status:   "run",
+		Start:    time.Now(),
+		Attempts: 1,
+	}
+	test.running = test.Start
+	r.tests[t] = test
+	if p, ok := r.tests[parent]; ok {
+		p.Children = append(p.Children, test)
+		test.Tags = append(test.Tags, p.Tags...) // tags are inherited from groups
+		test.inUnit = p.unit || p.inUnit
+		test.unit = !test.inUnit && isShardUnit(test.Name, n)
+	} else {
+		r.roots = append(r.roots, test)
+	}
+	test.Tags = append(test.Tags, node.Options().Tags...)
+	t.Cleanup(func() {
+		status := "pass"
+		switch {
+		case t.Skipped():
+			status = "skip"
+		case t.Failed():
+			status = "fail"
+		}
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		test.Status = status
+		test.End = time.Now()
+		delete(r.tests, t)
+	})
+}
+
+// running records that t started running after waiting for parallel slots and resources.
+func (r *reporter) running(t *testing.T) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if test, ok := r.tests[t]; ok {
+		test.running = time.Now()
+	}
+}
+
+// retried records the result of the last attempt of the test with provided full name run by [Main] (see [Retry]).
+// If the attempt passed, the test and its ancestors without other failed children are reported as passed.
+func (r *reporter) retried(name string, attempt int, passed bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	path := findReported(r.roots, name)
+	if len(path) == 0 {
+		return
+	}
+	test := path[len(path)-1]
+	test.Attempts = attempt
+	if !passed {
+		return
+	}
+	test.Status = "pass"
+	for i := len(path) - 2; i >= 0; i-- {
+		if path[i].Status != "fail" || anyFailed(path[i].Children) {
+			return
+		}
+		path[i].Status = "pass"
+	}
+}
+
+// findReported returns the reported test with provided full name preceded by its ancestors or nil if it is not found.
+func findReported(tests []*reportedTest, name string) []*reportedTest {
+	for _, test := range tests {
+		if test.Name == name {
+			return []*reportedTest{test}
+		}
+		if strings.HasPrefix(name, test.Name+"/") {
+			if path := findReported(test.Children, name); path != nil {
+				return append([]*reportedTest{test}, path...)
+			}
+		}
+	}
+	return nil
+}
+
+func anyFailed(tests []*reportedTest) bool {
+	for _, test := range tests {
+		if test.Status == "fail" {
+			return true
+		}
+	}
+	return false
+}
+
+// logsOf returns captured log lines of the test with provided full name and its subtests by full test name.
+func (r *reporter) logsOf(name string) map[string][]string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	logs := make(map[string][]string)
+	for test, lines := range r.logs {
+		if test == name || strings.HasPrefix(test, name+"/") {
+			logs[test] = lines
+		}
+	}
+	return logs
+}
+
+// failures returns full names of failed tests captured from the output of go test.
+func (r *reporter) failures() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.failed
+}
+
+// capture replaces stdout with a pipe to capture failed tests and log lines of tests.
+// The returned function restores stdout and waits until all output is processed.
+func (r *reporter) capture() (func(), error) {
+	stdout := os.Stdout
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	os.Stdout = writer
+	r.mu.Lock()
+	if r.logs == nil {
+		r.logs = make(map[string][]string)
+	}
+	r.mu.Unlock()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		r.parseLogs(io.TeeReader(reader, stdout))
+	}()
+	return func() {
+		os.Stdout = stdout
+		_ = writer.Close()
+		<-done
+		_ = reader.Close()
+	}, nil
+}
+
+var (
+	// testHeader matches lines of go test output which start output of a test.
+	testHeader = regexp.MustCompile(`^=== (?:RUN|CONT|NAME) +(\S.*)$`) //nolint:gochecknoglobals // compiled once
+	// testResult matches lines of go test output with the result of a test, they are followed by logs without -v.
+	testResult = regexp.MustCompile(`^ *--- (PASS|FAIL|SKIP): (.+) \(\d+\.\d+s\)$`) //nolint:gochecknoglobals // compiled once
+)
+
+// parseLogs attributes indented lines of go test output to the test printed before them
+// and collects failed tests.
+func (r *reporter) parseLogs(output io.Reader) {
+	scanner := bufio.NewScanner(output)
+	scanner.Buffer(nil, 1024*1024)
+	current := ""
+	for scanner.Scan() {
+		line := strings.TrimPrefix(scanner.Text(), "\x16") // marker of test2json mode
+		if match := testHeader.FindStringSubmatch(line); match != nil {
+			current = match[1]
+			continue
+		}
+		if match := testResult.FindStringSubmatch(line); match != nil {
+			current = match[2]
+			if match[1] == "FAIL" {
+				r.mu.Lock()
+				r.failed = append(r.failed, current)
+				r.mu.Unlock()
+			}
+			continue
+		}
+		if current == "" || !strings.HasPrefix(line, "    ") {
+			continue
+		}
+		r.mu.Lock()
+		r.logs[current] = append(r.logs[current], strings.TrimSpace(line))
+		r.mu.Unlock()
+	}
+	_, _ = io.Copy(io.Discard, output) // do not block tests if a line is too long
+}
+
+// write writes the JSON report to path atomically.
+func (r *reporter) write(path string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.attachLogs(r.roots)
+	data, err := json.MarshalIndent(report{
+		Start: r.start,
+		End:   time.Now(),
+		Tests: r.roots,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomically(path, data)
+}
+
+// attachLogs sets captured log lines to tests and their children.
+func (r *reporter) attachLogs(tests []*reportedTest) {
+	for _, test := range tests {
+		test.Logs = r.logs[test.Name]
+		r.attachLogs(test.Children)
+	}
+}
+
+// writeFileAtomically writes data to a temporary file and renames it to path,
+// so that readers never see a partially written file.
+func writeFileAtomically(path string, data []byte) error {
+	file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name()) //nolint:errcheck // the file does not exist after rename
+	if _, err := file.Write(data); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), path)
+}
+
+// reportSetting returns the value of environment variable env which configures a report or empty string if it is disabled.
+// Reports are never written by attempts run in separate processes (see [Retry]).
+func reportSetting(env string) string {
+	if os.Getenv(attemptEnv) != "" {
+		return ""
+	}
+	return os.Getenv(env)
+}
@@ -0,0 +1,204 @@
+package pt_test
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+type reportedTest struct {
+	Name     string          `json:"name"`
+	Kind     string          `json:"kind"`
+	Line     int             `json:"line"`
+	Tags     []string        `json:"tags"`
+	Status   string          `json:"status"`
+	Start    time.Time       `json:"start"`
+	End      time.Time       `json:"end"`
+	Attempts int             `json:"attempts"`
+	Logs     []string        `json:"logs"`
+	Children []*reportedTest `json:"children"`
+}
+
+func TestReport(t *testing.T) {
+	t.Parallel()
+	t.Run("should write report", func(t *testing.T) {
+		t.Parallel()
+		path := filepath.Join(t.TempDir(), "report.json")
+		output, err := runTestdata(t, "report", "PT_REPORT="+path)
+		if err == nil {
+			t.Fatalf("go test succeeded:\n%s", output)
+		}
+		assertContains(t, output, "--- FAIL: TestReport/group/failed ", "first line")
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var report struct {
+			Tests []*reportedTest `json:"tests"`
+		}
+		if err := json.Unmarshal(data, &report); err != nil {
+			t.Fatal(err)
+		}
+		if len(report.Tests) != 1 || report.Tests[0].Name != "TestReport" || len(report.Tests[0].Children) != 2 {
+			t.Fatalf("wrong report:\n%s", data)
+		}
+		root := report.Tests[0]
+		group := root.Children[0]
+		flaky := root.Children[1]
+		if root.Status != "fail" || group.Status != "fail" || group.Kind != "Group" || len(group.Children) != 3 {
+			t.Fatalf("wrong report:\n%s", data)
+		}
+		passed, failed, skipped := group.Children[0], group.Children[1], group.Children[2]
+		if passed.Status != "pass" || failed.Status != "fail" || skipped.Status != "skip" || flaky.Status != "pass" {
+			t.Errorf("wrong statuses:\n%s", data)
+		}
+		if passed.Start.IsZero() || passed.End.Before(passed.Start) || passed.Line == 0 || strings.Join(passed.Tags, ",") != "tag" {
+			t.Errorf("wrong test:\n%s", data)
+		}
+		if strings.Join(passed.Logs, "\n") != "report_test.go:18: first line\nreport_test.go:19: second line" {
+			t.Errorf("wrong logs of passed test: %q", passed.Logs)
+		}
+		if strings.Join(failed.Logs, "\n") != "report_test.go:22: failure message" {
+			t.Errorf("wrong logs of failed test: %q", failed.Logs)
+		}
+		if flaky.Attempts != 2 || passed.Attempts != 1 {
+			t.Errorf("wrong attempts:\n%s", data)
+		}
+	})
+	t.Run("should not write report by default", func(t *testing.T) {
+		t.Parallel()
+		path := filepath.Join(t.TempDir(), "report.json")
+		output, err := runTestdata(t, "report", "PT_REPORT=")
+		if err == nil {
+			t.Fatalf("go test succeeded:\n%s", output)
+		}
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("report is written: %v", err)
+		}
+		if entries, _ := os.ReadDir(filepath.Dir(path)); len(entries) != 0 {
+			t.Errorf("temporary files are not removed: %v", entries)
+		}
+	})
+}
+
+func TestJUnit(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "junit.xml")
+	output, err := runTestdata(t, "report", "PT_JUNIT="+path, "PT_REPORT=")
+	if err == nil {
+		t.Fatalf("go test succeeded:\n%s", output)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var suites struct {
+		Suites []struct {
+			Name     string `xml:"name,attr"`
+			Tests    int    `xml:"tests,attr"`
+			Failures int    `xml:"failures,attr"`
+			Skipped  int    `xml:"skipped,attr"`
+			Cases    []struct {
+				Name       string `xml:"name,attr"`
+				Classname  string `xml:"classname,attr"`
+				Properties []struct {
+					Name  string `xml:"name,attr"`
+					Value string `xml:"value,attr"`
+				} `xml:"properties>property"`
+				Failure *struct {
+					Message string `xml:"message,attr"`
+				} `xml:"failure"`
+				Skipped *struct {
+					Message string `xml:"message,attr"`
+				} `xml:"skipped"`
+			} `xml:"testcase"`
+		} `xml:"testsuite"`
+	}
+	if err := xml.Unmarshal(data, &suites); err != nil {
+		t.Fatal(err)
+	}
+	var actual []string
+	for _, suite := range suites.Suites {
+		actual = append(actual, fmt.Sprintf("suite %s %d/%d/%d", suite.Name, suite.Tests, suite.Failures, suite.Skipped))
+		for _, c := range suite.Cases {
+			description := "case " + c.Classname + " " + c.Name
+			for _, p := range c.Properties {
+				description += " " + p.Name + "=" + p.Value
+			}
+			if c.Failure != nil {
+				description += " failure: " + c.Failure.Message
+			}
+			if c.Skipped != nil {
+				description += " skipped: " + c.Skipped.Message
+			}
+			actual = append(actual, description)
+		}
+	}
+	assertEvents(t, actual,
+		"suite TestReport 1/0/0",
+		"case TestReport flaky attempts=2 flaky=true",
+		"suite TestReport/group 3/1/1",
+		"case TestReport/group passed tag=tag",
+		"case TestReport/group failed tag=tag failure: failure message",
+		"case TestReport/group skipped tag=tag skipped: pending",
+	)
+}
+
+func TestTrace(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "trace.json")
+	output, err := runTestdata(t, "report", "PT_TRACE="+path, "PT_REPORT=", "PT_JUNIT=")
+	if err == nil {
+		t.Fatalf("go test succeeded:\n%s", output)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	type event struct {
+		Name  string         `json:"name"`
+		Phase string         `json:"ph"`
+		TS    int64          `json:"ts"`
+		Dur   int64          `json:"dur"`
+		PID   int            `json:"pid"`
+		TID   int            `json:"tid"`
+		Args  map[string]any `json:"args"`
+	}
+	var trace struct {
+		TraceEvents []event `json:"traceEvents"`
+	}
+	if err := json.Unmarshal(data, &trace); err != nil {
+		t.Fatal(err)
+	}
+	var actual []string
+	lanes := map[[2]int][]event{}
+	for _, e := range trace.TraceEvents {
+		actual = append(actual, fmt.Sprintf("%s %d %s %v", e.Phase, e.PID, e.Name, e.Args["status"]))
+		if e.Phase != "X" {
+			continue
+		}
+		lane := [2]int{e.PID, e.TID}
+		for _, other := range lanes[lane] {
+			if e.TS < other.TS+other.Dur && other.TS < e.TS+e.Dur {
+				t.Errorf("%s overlaps with %s in the same lane", e.Name, other.Name)
+			}
+		}
+		lanes[lane] = append(lanes[lane], e)
+	}
+	sort.Strings(actual)
+	assertEvents(t, actual,
+		"M 1 process_name <nil>",
+		"M 2 process_name <nil>",
+		"X 1 TestReport/flaky pass",
+		"X 1 TestReport/group/failed fail",
+		"X 1 TestReport/group/passed pass",
+		"X 2 TestReport fail",
+		"X 2 TestReport/group fail",
+	)
+}
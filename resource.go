@@ -0,0 +1,197 @@
+package pt
+
+import (
+	"sync"
+	"testing"
+)
+
+/*
+Shared is an [Option] which makes the test claim named resources in shared mode.
+A test claiming a resource in shared mode can overlap with other tests claiming it in shared mode,
+but not with tests claiming it in [Exclusive] mode.
+Resources are global for the test binary: tests of all [Parallel] and [PackageParallel] calls are taken into account.
+
+When applied to a group, each test of the group (including nested groups) claims the resources.
+The test waits until all its resources are available and claims them at once,
+so tests claiming several resources do not deadlock.
+A test waiting for a resource in [Exclusive] mode blocks tests which claim it in shared mode later,
+so it is not delayed forever by overlapping shared claims.
+
+	pt.Test("should read config", testReadConfig, pt.Shared("testdata/config.json"))
+
+Resources are released when the test is finished (after AfterEach hooks).
+A test claiming resources can't run tests by pt inside of its body (e.g. nested [Parallel] call),
+because nested tests and tests waiting for the resources would wait for each other forever, such test fails.
+Claim resources by the group instead.
+*/
+func Shared(resources ...string) Option {
+	return claim(false, resources)
+}
+
+/*
+Exclusive is an [Option] which makes the test claim named resources in exclusive mode.
+A test claiming a resource in exclusive mode does not overlap with any other test claiming the same resource,
+but still runs in parallel with the rest tests.
+See [Shared] for details.
+
+	pt.Test("should listen port", testListen, pt.Exclusive("port:8080"))
+*/
+func Exclusive(resources ...string) Option {
+	return claim(true, resources)
+}
+
+func claim(exclusive bool, resources []string) Option {
+	c := make(claims, len(resources))
+	for _, resource := range resources {
+		c[resource] = exclusive
+	}
+	return func(target *node) {
+		// copy claims, because target can be a copy of another node made by With
+		target.resources = claims(nil).merge(target.resources).merge(c)
+	}
+}
+
+// claims maps resource name to true if it is claimed in exclusive mode.
+type claims map[string]bool
+
+// merge adds other claims to c, exclusive mode wins.
+func (c claims) merge(other claims) claims {
+	if len(other) == 0 {
+		return c
+	}
+	if c == nil {
+		c = make(claims, len(other))
+	}
+	for resource, exclusive := range other {
+		c[resource] = c[resource] || exclusive
+	}
+	return c
+}
+
+// claimsOf returns resources claimed by test n and all groups it belongs to.
+func (s *scope) claimsOf(n *node) claims {
+	var result claims
+	if n != nil {
+		result = result.merge(n.resources)
+	}
+	for ; s != nil; s = s.parent {
+		if s.owner != nil {
+			result = result.merge(s.owner.resources)
+		}
+	}
+	return result
+}
+
+// resourceLocks is a set of named read-write locks which are acquired all at once.
+// A waiting exclusive claim blocks new shared claims of the resource, so that exclusive claims are not starved.
+type resourceLocks struct {
+	mu        sync.Mutex
+	released  *sync.Cond
+	shared    map[string]int
+	exclusive map[string]bool
+	waiting   map[string]int  // number of waiting exclusive claims
+	holders   map[string]bool // full names of tests holding claims
+}
+
+var resources = newResourceLocks() //nolint:gochecknoglobals // resources are global for the test binary
+
+func newResourceLocks() *resourceLocks {
+	l := &resourceLocks{
+		shared:    make(map[string]int),
+		exclusive: make(map[string]bool),
+		waiting:   make(map[string]int),
+		holders:   make(map[string]bool),
+	}
+	l.released = sync.NewCond(&l.mu)
+	return l
+}
+
+// acquire blocks until all resources and the environment are available and claims them at once.
+// Unless c claims the environment exclusively (see [Env]), it is claimed in shared mode
+// until t is finished or runs tests by pt inside of its body.
+// Resources are released when t is finished.
+func (l *resourceLocks) acquire(t *testing.T, c claims) {
+	if _, ok := c[envResource]; ok {
+		l.lock(c)
+	} else {
+		l.lock(claims{envResource: false}.merge(c))
+		t.Cleanup(yieldable(t, func() { l.release(claims{envResource: false}) }))
+	}
+	if len(c) == 0 {
+		return
+	}
+	l.mu.Lock()
+	l.holders[t.Name()] = true
+	l.mu.Unlock()
+	t.Cleanup(func() {
+		l.mu.Lock()
+		delete(l.holders, t.Name())
+		l.mu.Unlock()
+		l.release(c)
+	})
+}
+
+// holds returns true if t or any of its ancestors (e.g. a test wrapping t.Run around nested tests)
+// holds claims acquired by [resourceLocks.acquire].
+func (l *resourceLocks) holds(t *testing.T) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, name := range lineage(t) {
+		if l.holders[name] {
+			return true
+		}
+	}
+	return false
+}
+
+// lock blocks until all resources are available and claims them.
+func (l *resourceLocks) lock(c claims) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.wait(c, 1)
+	for !l.available(c) {
+		l.released.Wait()
+	}
+	l.wait(c, -1)
+	for resource, exclusive := range c {
+		if exclusive {
+			l.exclusive[resource] = true
+		} else {
+			l.shared[resource]++
+		}
+	}
+}
+
+// wait adds delta to the number of waiting exclusive claims of resources claimed exclusively by c.
+func (l *resourceLocks) wait(c claims, delta int) {
+	for resource, exclusive := range c {
+		if !exclusive {
+			continue
+		}
+		if l.waiting[resource] += delta; l.waiting[resource] == 0 {
+			delete(l.waiting, resource)
+		}
+	}
+}
+
+func (l *resourceLocks) available(c claims) bool {
+	for resource, exclusive := range c {
+		if l.exclusive[resource] || exclusive && l.shared[resource] > 0 || !exclusive && l.waiting[resource] > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (l *resourceLocks) release(c claims) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for resource, exclusive := range c {
+		if exclusive {
+			delete(l.exclusive, resource)
+		} else if l.shared[resource]--; l.shared[resource] == 0 {
+			delete(l.shared, resource)
+		}
+	}
+	l.released.Broadcast()
+}
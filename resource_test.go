@@ -0,0 +1,131 @@
+package pt_test
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/maratori/pt"
+)
+
+func TestResources(t *testing.T) {
+	t.Parallel()
+	t.Run("should not overlap tests with exclusive resource", func(t *testing.T) {
+		t.Parallel()
+		resource := t.Name()
+		var counter concurrencyCounter
+		t.Run("internal", func(it *testing.T) {
+			pt.Parallel(it,
+				pt.Test("", counter.test(100*time.Millisecond), pt.Exclusive(resource)),
+				pt.Test("", counter.test(100*time.Millisecond), pt.Exclusive(resource)),
+				pt.Test("", counter.test(100*time.Millisecond), pt.Exclusive(resource)),
+			)
+		})
+		if peak := counter.max(); peak != 1 {
+			t.Errorf("max running tests %d != 1", peak)
+		}
+	})
+	t.Run("should overlap tests with shared resource", func(t *testing.T) {
+		t.Parallel()
+		resource := t.Name()
+		meeting := newRendezvous(2)
+		t.Run("internal", func(it *testing.T) {
+			pt.Parallel(it,
+				pt.Test("", meeting.test, pt.Shared(resource)),
+				pt.Test("", meeting.test, pt.Shared(resource)),
+			)
+		})
+	})
+	t.Run("should not overlap shared and exclusive claims", func(t *testing.T) {
+		t.Parallel()
+		resource := t.Name()
+		var running int32
+		shared := func(*testing.T) {
+			atomic.AddInt32(&running, 1)
+			time.Sleep(100 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+		}
+		t.Run("internal", func(it *testing.T) {
+			pt.Parallel(it,
+				pt.Test("", shared, pt.Shared(resource)),
+				pt.Test("", shared, pt.Shared(resource)),
+				pt.Test("", func(t *testing.T) {
+					time.Sleep(100 * time.Millisecond)
+					if atomic.LoadInt32(&running) != 0 {
+						t.Error("exclusive test overlaps with shared one")
+					}
+				}, pt.Exclusive(resource)),
+			)
+		})
+	})
+	t.Run("should apply group claims to each test", func(t *testing.T) {
+		t.Parallel()
+		resource := t.Name()
+		var counter concurrencyCounter
+		t.Run("internal", func(it *testing.T) {
+			pt.Parallel(it,
+				pt.With(pt.Group("",
+					pt.Test("", counter.test(100*time.Millisecond)),
+					pt.Group("",
+						pt.Test("", counter.test(100*time.Millisecond)),
+					),
+				), pt.Exclusive(resource)),
+				pt.Test("", counter.test(100*time.Millisecond), pt.Shared(resource)),
+			)
+		})
+		if peak := counter.max(); peak != 1 {
+			t.Errorf("max running tests %d != 1", peak)
+		}
+	})
+	t.Run("should not deadlock with several resources", func(t *testing.T) {
+		t.Parallel()
+		a, b := t.Name()+"a", t.Name()+"b"
+		var counter concurrencyCounter
+		t.Run("internal", func(it *testing.T) {
+			pt.Parallel(it,
+				pt.Test("", counter.test(50*time.Millisecond), pt.Exclusive(a, b)),
+				pt.Test("", counter.test(50*time.Millisecond), pt.Exclusive(b), pt.Exclusive(a)),
+				pt.With(pt.Group("",
+					pt.Test("", counter.test(50*time.Millisecond), pt.Exclusive(a)),
+				), pt.Exclusive(b)),
+				pt.Test("", counter.test(50*time.Millisecond), pt.Shared(a)),
+				pt.Test("", counter.test(50*time.Millisecond), pt.Shared(b)),
+			)
+		})
+		if peak := counter.max(); peak > 2 {
+			t.Errorf("max running tests %d > 2", peak)
+		}
+	})
+	t.Run("should not change claims of original test", func(t *testing.T) {
+		t.Parallel()
+		resource := t.Name()
+		meeting := newRendezvous(2)
+		test := pt.Test("", meeting.test, pt.Shared(resource))
+		pt.With(test, pt.Exclusive(resource))
+		t.Run("internal", func(it *testing.T) {
+			pt.Parallel(it, test, test)
+		})
+	})
+	t.Run("should fail test with claims running nested tests", func(t *testing.T) {
+		t.Parallel()
+		output, err := runTestdata(t, "resource", "GOFLAGS=-run=^TestNested -parallel=1 -timeout=1m")
+		if err == nil {
+			t.Fatalf("go test succeeded:\n%s", output)
+		}
+		assertContains(t, output,
+			"tests can't be run by pt inside of the body of a test which claims resources",
+			"--- FAIL: TestNested/outer ",
+			"--- PASS: TestNested/other ",
+			"--- FAIL: TestNestedInSubtest/outer/wrap ",
+		)
+		assertNotContains(t, output, "nested test is run")
+	})
+	t.Run("should not start shared claims while exclusive claim waits", func(t *testing.T) {
+		t.Parallel()
+		output, err := runTestdata(t, "resource", "GOFLAGS=-run=^TestExclusivePriority$ -parallel=4 -timeout=1m")
+		if err != nil {
+			t.Fatalf("go test failed: %s\n%s", err, output)
+		}
+		assertContains(t, output, "--- PASS: TestExclusivePriority/shared_group/second_shared ")
+	})
+}
@@ -0,0 +1,249 @@
+package pt
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// attemptEnv is the environment variable set for the test binary re-executed to run an attempt of a test.
+const attemptEnv = "PT_ATTEMPT"
+
+/*
+Retry is an [Option] which re-runs a failed test up to attempts times in total with backoff between attempts.
+The test fails only if all attempts fail.
+When the test passes not on the first attempt, it is reported as flaky.
+
+Retry works only if tests are run by [Main]. The first attempt runs in the current process as usual.
+A failed subtest always fails its parent in go, so when all tests are finished, Main re-runs failed tests
+with retry policy in separate processes: the test binary is re-executed with -test.run matching only the test
+and the rest flags of the current process, one attempt per process.
+The process runs the top level test function the test belongs to, so the code of that function,
+hooks and fixtures of all groups the test belongs to are run again in every attempt.
+Every attempt gets the whole [Timeout] of the test.
+If all failed tests of the package pass on retry, Main returns zero exit code.
+Tests are not retried with -failfast flag of go test, because the rest tests are not run after the first failure.
+
+When applied to a group, each test of the group (including nested groups) gets the retry policy,
+unless the test or a nested group has its own one.
+
+	pt.Test("should receive message", testReceive, pt.Retry(3, time.Second))
+*/
+func Retry(attempts int, backoff time.Duration) Option {
+	if attempts < 1 {
+		panic("argument attempts must be positive")
+	}
+	if backoff < 0 {
+		panic("argument backoff can not be negative")
+	}
+	return func(target *node) {
+		target.retry = &retryPolicy{
+			attempts: attempts,
+			backoff:  backoff,
+		}
+	}
+}
+
+type retryPolicy struct {
+	attempts int
+	backoff  time.Duration
+}
+
+// retryOf returns the retry policy of test n or of the closest group it belongs to.
+func (s *scope) retryOf(n *node) *retryPolicy {
+	if n != nil && n.retry != nil {
+		return n.retry
+	}
+	for ; s != nil; s = s.parent {
+		if s.owner != nil && s.owner.retry != nil {
+			return s.owner.retry
+		}
+	}
+	return nil
+}
+
+// watch records t to be retried by [Main] if it fails.
+// It must be called before AfterEach hooks are scheduled, so that failures of hooks are taken into account.
+func (p *retryPolicy) watch(t *testing.T) {
+	if p == nil || p.attempts == 1 || os.Getenv(attemptEnv) != "" {
+		return
+	}
+	t.Cleanup(func() {
+		if t.Failed() {
+			retries.add(t.Name(), p)
+		}
+	})
+}
+
+// failedTest is a failed test to be retried.
+type failedTest struct {
+	name   string
+	policy *retryPolicy
+}
+
+type failedTests struct {
+	mu    sync.Mutex
+	tests []failedTest
+}
+
+var retries failedTests //nolint:gochecknoglobals // failed tests are retried at the end of the test binary
+
+func (f *failedTests) add(name string, policy *retryPolicy) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.tests = append(f.tests, failedTest{name: name, policy: policy})
+}
+
+// run retries failed tests and returns the exit code of the test binary.
+// The code is zero if all failed tests (see failed) are retried tests passed on retry or their ancestors.
+func (f *failedTests) run(w io.Writer, code int, failed []string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if code == 0 || len(f.tests) == 0 || failFastEnabled() {
+		return code
+	}
+	sort.Slice(f.tests, func(i, j int) bool {
+		return f.tests[i].name < f.tests[j].name
+	})
+	allPassed := true
+	for _, test := range f.tests {
+		allPassed = test.retry(w) && allPassed
+	}
+	if !allPassed || len(failed) == 0 {
+		return code
+	}
+	for _, name := range failed {
+		if !f.explains(name) {
+			return code
+		}
+	}
+	return 0
+}
+
+// explains returns true if the test with provided full name is a retried test or an ancestor of one.
+func (f *failedTests) explains(name string) bool {
+	for _, test := range f.tests {
+		if test.name == name || strings.HasPrefix(test.name, name+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// retry runs attempts of the test except the first one in separate processes until an attempt passes.
+func (test failedTest) retry(w io.Writer) bool {
+	attempts := test.policy.attempts
+	for attempt := 2; attempt <= attempts; attempt++ {
+		time.Sleep(test.policy.backoff)
+		output, passed := runAttempt(test.name, attempt)
+		if passed {
+			reports.retried(test.name, attempt, true)
+			flakes.add(test.name, attempt, attempts)
+			return true
+		}
+		fmt.Fprintf(w, "\nRETRY: %s attempt %d of %d failed:\n%s", test.name, attempt, attempts, output)
+	}
+	reports.retried(test.name, attempts, false)
+	return false
+}
+
+// runAttempt re-executes the test binary to run only the test with provided full name.
+func runAttempt(name string, attempt int) (string, bool) {
+	cmd := exec.Command(os.Args[0], attemptArgs(name)...) //nolint:gosec // the same binary is re-executed
+	cmd.Env = append(os.Environ(), attemptEnv+"="+strconv.Itoa(attempt))
+	output, err := cmd.CombinedOutput()
+	passed := err == nil && strings.Contains(string(output), "--- PASS: "+name+" (")
+	return string(output), passed
+}
+
+// notForwardedFlags are flags of the test binary which are not passed to the process running an attempt,
+// because they are set for the attempt or make the process run other tests or overwrite files of the current process.
+var notForwardedFlags = map[string]bool{ //nolint:gochecknoglobals // constant set
+	"test.run":          true,
+	"test.skip":         true,
+	"test.count":        true,
+	"test.v":            true,
+	"test.list":         true,
+	"test.bench":        true,
+	"test.fuzz":         true,
+	"test.testlogfile":  true,
+	"test.coverprofile": true,
+	"test.cpuprofile":   true,
+	"test.memprofile":   true,
+	"test.blockprofile": true,
+	"test.mutexprofile": true,
+	"test.trace":        true,
+}
+
+// attemptArgs returns arguments of the test binary to run only the test with provided full name
+// with the rest flags and arguments of the current process.
+func attemptArgs(name string) []string {
+	args := []string{"-test.run=" + runPattern(name), "-test.count=1", "-test.v=true"}
+	flag.Visit(func(f *flag.Flag) {
+		if !notForwardedFlags[f.Name] {
+			args = append(args, "-"+f.Name+"="+f.Value.String())
+		}
+	})
+	if len(flag.Args()) > 0 {
+		args = append(append(args, "--"), flag.Args()...)
+	}
+	return args
+}
+
+// runPattern returns the value of -test.run flag which matches only the test with provided full name.
+func runPattern(name string) string {
+	elements := strings.Split(name, "/")
+	for i, element := range elements {
+		elements[i] = "^" + regexp.QuoteMeta(element) + "$"
+	}
+	return strings.Join(elements, "/")
+}
+
+// flakyTest is a test which passed not on the first attempt.
+type flakyTest struct {
+	name     string
+	attempt  int
+	attempts int
+}
+
+type flakyTests struct {
+	mu    sync.Mutex
+	tests []flakyTest
+}
+
+var flakes flakyTests //nolint:gochecknoglobals // flaky tests are reported at the end of the test binary
+
+func (f *flakyTests) add(name string, attempt int, attempts int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.tests = append(f.tests, flakyTest{
+		name:     name,
+		attempt:  attempt,
+		attempts: attempts,
+	})
+}
+
+// print writes the summary of flaky tests to w.
+func (f *flakyTests) print(w io.Writer) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.tests) == 0 {
+		return
+	}
+	sort.Slice(f.tests, func(i, j int) bool {
+		return f.tests[i].name < f.tests[j].name
+	})
+	fmt.Fprintf(w, "\nFLAKY: %d test(s) passed after retry\n", len(f.tests))
+	for _, test := range f.tests {
+		fmt.Fprintf(w, "FLAKY: %s passed on attempt %d of %d\n", test.name, test.attempt, test.attempts)
+	}
+}
@@ -0,0 +1,76 @@
+package pt_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/maratori/pt"
+)
+
+func TestRetry(t *testing.T) {
+	t.Parallel()
+	t.Run("should panic on non-positive attempts", func(t *testing.T) {
+		t.Parallel()
+		defer assertPanic(t, "argument attempts must be positive")
+		pt.Retry(0, 0)
+	})
+	t.Run("should panic on negative backoff", func(t *testing.T) {
+		t.Parallel()
+		defer assertPanic(t, "argument backoff can not be negative")
+		pt.Retry(1, -1)
+	})
+	t.Run("should pass flaky tests and report them", func(t *testing.T) {
+		t.Parallel()
+		output, err := runTestdata(t, "retry", "PT_TESTDATA_FAIL=")
+		if err != nil {
+			t.Fatalf("go test failed: %s\n%s", err, output)
+		}
+		assertContains(t, output,
+			"--- FAIL: TestRetry/fails_on_first_attempt ",
+			"first attempt failed",
+			`attempt "" failed`,
+			"RETRY: TestRetry/group/passes_on_last_attempt attempt 2 of 3 failed:",
+			`attempt "2" failed`,
+			`attempt "" passed`,
+			"FLAKY: 2 test(s) passed after retry",
+			"FLAKY: TestRetry/fails_on_first_attempt passed on attempt 2 of 3",
+			"FLAKY: TestRetry/group/passes_on_last_attempt passed on attempt 3 of 3",
+		)
+		assertNotContains(t, output, "RETRY: TestRetry/fails_on_first_attempt attempt", "TestRetry/passes_on_first_attempt attempt")
+		if strings.Contains(output, "TestRetry/passes_on_first_attempt passed on attempt") {
+			t.Errorf("test passed on first attempt is reported as flaky:\n%s", output)
+		}
+	})
+	t.Run("should fail if all attempts fail", func(t *testing.T) {
+		t.Parallel()
+		output, err := runTestdata(t, "retry", "PT_TESTDATA_FAIL=always")
+		if err == nil {
+			t.Fatalf("go test succeeded:\n%s", output)
+		}
+		assertContains(t, output,
+			"--- FAIL: TestAlwaysFails/always_fails",
+			"RETRY: TestAlwaysFails/always_fails attempt 2 of 2 failed:",
+		)
+	})
+	t.Run("should fail if test without retry fails", func(t *testing.T) {
+		t.Parallel()
+		output, err := runTestdata(t, "retry", "PT_TESTDATA_FAIL=not retried")
+		if err == nil {
+			t.Fatalf("go test succeeded:\n%s", output)
+		}
+		assertContains(t, output,
+			"--- FAIL: TestNotRetried/not_retried",
+			"FLAKY: TestNotRetried/flaky passed on attempt 2 of 2",
+		)
+		assertNotContains(t, output, "test without retry is run in attempt")
+	})
+	t.Run("should forward flags to attempt", func(t *testing.T) {
+		t.Parallel()
+		output, err := runTestdata(t, "retry", "PT_TESTDATA_FAIL=", "GOFLAGS=-short")
+		if err != nil {
+			t.Fatalf("go test failed: %s\n%s", err, output)
+		}
+		assertContains(t, output, "FLAKY: TestFlags/gets_flags passed on attempt 2 of 2")
+		assertNotContains(t, output, "flags are not forwarded to attempt")
+	})
+}
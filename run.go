@@ -0,0 +1,130 @@
+package pt
+
+import (
+	"runtime/pprof"
+	"sync"
+	"testing"
+)
+
+// run runs tests as subtests of t.
+// The owner is the node which tests belong to, it is nil for top level calls.
+// If parallel is true, subtests run in parallel and run returns immediately,
+// otherwise subtests run one by one and run returns when all of them are finished.
+func run(t *testing.T, owner *node, tests []testing.InternalTest, parallel bool) {
+	if listFormat() != "" {
+		list(t, owner, tests)
+		return
+	}
+	if resources.holds(t) {
+		t.Fatal("tests can't be run by pt inside of the body of a test which claims resources or has Env, " +
+			"claim them by the group")
+	}
+	yield(t)
+	reports.startRoot(t, parallel)
+	parent := t
+	Context(t) // tests of the group derive their contexts from it, so it is created before they are started
+	s, children := newScope(t, owner, tests)
+	s.start()
+	if parallel {
+		if s.shuffled() {
+			s.shuffle(children)
+		} else {
+			sortByHistory(t, children)
+		}
+	}
+	for _, test := range children {
+		test := test
+		n := lookup(test)
+		t.Run(test.Name, func(t *testing.T) {
+			reports.startTest(t, parent, n)
+			if n != nil && n.pending {
+				t.Skip("pending")
+			}
+			s.skipUntagged(t, n)
+			skipOtherShards(t, n)
+			if n == nil || n.kind == KindTest || n.focused {
+				s.skipUnfocused(t, n)
+			} else {
+				s.skipUnfocusedGroup(t, n)
+			}
+			if parallel {
+				setParallel(t)
+				reports.running(t)
+			}
+			if count := stressCount(t.Name()); count > 0 {
+				s.repeat(t, n, test, count)
+				return
+			}
+			s.runChild(t, n, test)
+		})
+	}
+}
+
+// runChild runs test n (nil if test is not built by pt) as t which belongs to the scope.
+func (s *scope) runChild(t *testing.T, n *node, test testing.InternalTest) {
+	bind(t, s)
+	if n == nil || n.kind == KindTest {
+		s.runTest(t, n, test)
+	} else {
+		test.F(t)
+	}
+}
+
+// runTest runs a single test n (nil if test is not built by pt) with all settings of the scope.
+func (s *scope) runTest(t *testing.T, n *node, test testing.InternalTest) {
+	release := s.acquireSlots(t)
+	defer release()
+	env := s.envOf(n)
+	c := s.claimsOf(n)
+	if len(env) > 0 {
+		c = c.merge(claims{envResource: true})
+	}
+	resources.acquire(t, c)
+	setEnv(t, env)
+	reports.running(t)
+	if s.detectLeaksOf(n) {
+		watchLeaks(t)
+	}
+	timeout := s.timeoutOf(n)
+	ctx := newContext(t, timeout)
+	// label the goroutine, so that goroutines started by the test can be attributed to it
+	pprof.SetGoroutineLabels(ctx)
+	s.retryOf(n).watch(t)
+	var expired <-chan struct{}
+	if timeout > 0 {
+		expired = watchTimeout(t, ctx, timeout)
+	}
+	t.Cleanup(func() { s.runAfterEach(t) })
+	runUntil(t, expired, func() {
+		s.runBeforeEach(t)
+		test.F(t)
+	})
+}
+
+// yields maps full names of tests to functions releasing slots and claims
+// which tests run by pt inside of them may wait for.
+var yields sync.Map //nolint:gochecknoglobals // tests are run concurrently
+
+// yieldable returns release which is called once: either by the caller or when t runs tests by pt inside of its body.
+func yieldable(t *testing.T, release func()) func() {
+	var once sync.Once
+	wrapped := func() { once.Do(release) }
+	value, _ := yields.Load(t.Name())
+	list, _ := value.([]func())
+	yields.Store(t.Name(), append(list, wrapped))
+	t.Cleanup(func() { yields.Delete(t.Name()) })
+	return wrapped
+}
+
+// yield releases slots and claims held by t or any of its ancestors (e.g. a test wrapping t.Run around nested tests),
+// because tests run by pt inside of them would wait for them forever.
+func yield(t *testing.T) {
+	for _, name := range lineage(t) {
+		if value, ok := yields.LoadAndDelete(name); ok {
+			list, _ := value.([]func())
+			for _, release := range list {
+				release()
+			}
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package pt
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+// scope holds the state of a single [Parallel] call.
+// Scopes of nested groups are linked to the scope of the outer group,
+// so that settings declared in the outer group are inherited by nested groups.
+type scope struct {
+	t          *testing.T
+	parent     *scope
+	owner      *node // nil for top level calls
+	beforeAll  []func(t *testing.T)
+	afterAll   []func(t *testing.T)
+	beforeEach []func(t *testing.T)
+	afterEach  []func(t *testing.T)
+	slots      chan struct{} // nil if the number of running tests is not limited
+
+	mu       sync.Mutex
+	provided map[any]bool
+	fixtures map[any]any
+}
+
+// newScope creates a scope for a [run] call with t.
+// Hooks and other declarations are taken from tests, the rest tests are returned to be run as subtests.
+func newScope(t *testing.T, owner *node, tests []testing.InternalTest) (*scope, []testing.InternalTest) {
+	s := &scope{
+		t:      t,
+		parent: scopeOf(t),
+		owner:  owner,
+	}
+	if owner != nil && owner.limit > 0 {
+		s.slots = make(chan struct{}, owner.limit)
+	}
+	children := make([]testing.InternalTest, 0, len(tests))
+	for _, test := range tests {
+		n := lookup(test)
+		switch {
+		case n != nil && n.kind == KindHook:
+			s.addHook(n)
+		case n != nil && n.kind == KindProvide:
+			s.provide(n.fixture)
+		case n != nil && n.kind == KindCases:
+			children = append(children, n.children...)
+		default:
+			children = append(children, test)
+		}
+	}
+	return s, children
+}
+
+func (s *scope) addHook(n *node) {
+	switch n.hook {
+	case hookBeforeAll:
+		s.beforeAll = append(s.beforeAll, n.f)
+	case hookAfterAll:
+		s.afterAll = append(s.afterAll, n.f)
+	case hookBeforeEach:
+		s.beforeEach = append(s.beforeEach, n.f)
+	case hookAfterEach:
+		s.afterEach = append(s.afterEach, n.f)
+	}
+}
+
+// start runs BeforeAll hooks and schedules AfterAll hooks.
+func (s *scope) start() {
+	for _, hook := range s.afterAll {
+		hook := hook
+		s.t.Cleanup(func() { hook(s.t) }) // subtests are finished before cleanup
+	}
+	for _, hook := range s.beforeAll {
+		hook(s.t)
+	}
+}
+
+// scopes maps subtest started by pt to the scope it belongs to.
+var scopes sync.Map //nolint:gochecknoglobals // subtests of different tests are run concurrently
+
+// scopeOf returns the scope t belongs to or nil if t is not started by pt.
+func scopeOf(t *testing.T) *scope {
+	value, ok := scopes.Load(t)
+	if !ok {
+		return nil
+	}
+	s, _ := value.(*scope)
+	return s
+}
+
+// bind makes t belong to the scope s until t is finished.
+func bind(t *testing.T, s *scope) {
+	scopes.Store(t, s)
+	t.Cleanup(func() {
+		scopes.Delete(t)
+	})
+}
+
+// runBeforeEach runs BeforeEach hooks of s and all its parents.
+// Hooks of outer scopes are run first.
+func (s *scope) runBeforeEach(t *testing.T) {
+	if s == nil {
+		return
+	}
+	s.parent.runBeforeEach(t)
+	for _, hook := range s.beforeEach {
+		hook(t)
+	}
+}
+
+// runAfterEach runs AfterEach hooks of s and all its parents.
+// Hooks of inner scopes are run first.
+func (s *scope) runAfterEach(t *testing.T) {
+	if s == nil {
+		return
+	}
+	for _, hook := range s.afterEach {
+		hook(t)
+	}
+	s.parent.runAfterEach(t)
+}
+
+// lineage returns the full name of t and full names of all its ancestors, the closest first.
+func lineage(t *testing.T) []string {
+	name := t.Name()
+	names := []string{name}
+	for i := strings.LastIndex(name, "/"); i >= 0; i = strings.LastIndex(name, "/") {
+		name = name[:i]
+		names = append(names, name)
+	}
+	return names
+}
@@ -0,0 +1,167 @@
+package pt
+
+import (
+	"fmt"
+	"hash/fnv"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// shardEnv is the environment variable which selects the shard of tests to run: i/n, where 1 <= i <= n.
+const shardEnv = "PT_SHARD"
+
+// shard holds parsed value of PT_SHARD environment variable.
+var shard struct { //nolint:gochecknoglobals // environment is parsed once
+	once  sync.Once
+	value string
+	index uint32 // from 0
+	count uint32 // 0 if sharding is disabled
+}
+
+// shardSettings returns index (from 0) and count of shards, count is 0 if sharding is disabled.
+func shardSettings() (uint32, uint32) {
+	shard.once.Do(func() {
+		shard.value = os.Getenv(shardEnv)
+		if shard.value != "" {
+			shard.index, shard.count = parseShard(shard.value)
+		}
+	})
+	return shard.index, shard.count
+}
+
+func parseShard(value string) (uint32, uint32) {
+	i, n, ok := strings.Cut(value, "/")
+	index, err1 := strconv.ParseUint(i, 10, 32)
+	count, err2 := strconv.ParseUint(n, 10, 32)
+	if !ok || err1 != nil || err2 != nil || index < 1 || index > count {
+		panic(fmt.Sprintf("invalid %s value %q: expected i/n, where 1 <= i <= n", shardEnv, value))
+	}
+	return uint32(index - 1), uint32(count)
+}
+
+// inShard returns true if the test with provided full name belongs to the current shard.
+// Tests known from duration history are balanced between shards (see [balanceShards]), the rest are distributed by hash.
+func inShard(name string) bool {
+	index, count := shardSettings()
+	if count == 0 {
+		return true
+	}
+	if i, ok := historyShard(name); ok {
+		return i == index
+	}
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(name))
+	return hash.Sum32()%count == index
+}
+
+// skipOtherShards skips the test n (nil if test is not built by pt) if it belongs to another shard.
+// A group is skipped if none of its tests belong to the current shard, so that its hooks are not run in vain.
+func skipOtherShards(t *testing.T, n *node) {
+	if _, count := shardSettings(); count == 0 {
+		return
+	}
+	if isShardUnit(t.Name(), n) {
+		if !inShard(t.Name()) {
+			t.Skipf("skipped by %s=%s", shardEnv, shard.value)
+		}
+		return
+	}
+	if !n.anyInShard(t.Name()) {
+		t.Skipf("skipped by %s=%s, no tests of the group belong to the shard", shardEnv, shard.value)
+	}
+}
+
+// isShardUnit returns true if the test with provided full name is sharded as a whole.
+// Tests, repeated tests and arbitrary [testing.InternalTest] are sharded as a whole.
+func isShardUnit(name string, n *node) bool {
+	return n == nil || n.kind == KindTest || n.kind == KindRepeat || stressCount(name) > 0
+}
+
+// anyInShard returns true if at least one test of the group n with provided full name belongs to the current shard.
+// Full names of tests are predicted the same way go test builds them.
+func (n *node) anyInShard(name string) bool {
+	seen := make(map[string]int)
+	for _, test := range subtests(n.children) {
+		full := uniqueName(seen, name, rewriteName(test.Name))
+		child := lookup(test)
+		if isShardUnit(full, child) {
+			if inShard(full) {
+				return true
+			}
+		} else if child.anyInShard(full) {
+			return true
+		}
+	}
+	return false
+}
+
+// subtests returns tests which are run as subtests, hooks and other declarations are skipped.
+func subtests(tests []testing.InternalTest) []testing.InternalTest {
+	result := make([]testing.InternalTest, 0, len(tests))
+	for _, test := range tests {
+		n := lookup(test)
+		switch {
+		case n != nil && (n.kind == KindHook || n.kind == KindProvide):
+		case n != nil && n.kind == KindCases:
+			result = append(result, subtests(n.children)...)
+		default:
+			result = append(result, test)
+		}
+	}
+	return result
+}
+
+// uniqueName returns unique full name of a subtest the same way go test does (e.g. adds "#01" suffix).
+// The map seen contains names of already started siblings.
+func uniqueName(seen map[string]int, parent string, subname string) string {
+	name := parent + "/" + subname
+	empty := subname == ""
+	for {
+		next, exists := seen[name]
+		if !empty && !exists {
+			seen[name] = 1
+			return name
+		}
+		seen[name] = next + 1
+		name = fmt.Sprintf("%s#%02d", name, next)
+		empty = false
+	}
+}
+
+// rewriteName replaces spaces and escapes unprintable characters in the name of a subtest the same way go test does.
+func rewriteName(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		switch {
+		case isSpace(r):
+			b.WriteRune('_')
+		case !strconv.IsPrint(r):
+			quoted := strconv.QuoteRune(r)
+			b.WriteString(quoted[1 : len(quoted)-1])
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func isSpace(r rune) bool {
+	if r < 0x2000 {
+		switch r {
+		case '\t', '\n', '\v', '\f', '\r', ' ', 0x85, 0xA0, 0x1680:
+			return true
+		}
+	} else {
+		if r <= 0x200a {
+			return true
+		}
+		switch r {
+		case 0x2028, 0x2029, 0x202f, 0x205f, 0x3000:
+			return true
+		}
+	}
+	return false
+}
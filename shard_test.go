@@ -0,0 +1,77 @@
+package pt_test
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"testing"
+)
+
+func TestShard(t *testing.T) {
+	t.Parallel()
+	t.Run("should run every test in exactly one shard", func(t *testing.T) {
+		t.Parallel()
+		passed := regexp.MustCompile(`--- PASS: (\S+) `)
+		skippedGroup := regexp.MustCompile(`--- SKIP: (TestShard/[^/\s]+) `)
+		var actual []string
+		for i := 1; i <= 3; i++ {
+			output, err := runTestdata(t, "shard", fmt.Sprintf("PT_SHARD=%d/3", i))
+			if err != nil {
+				t.Fatalf("go test failed: %s\n%s", err, output)
+			}
+			for _, match := range passed.FindAllStringSubmatch(output, -1) {
+				actual = append(actual, match[1])
+			}
+			for _, match := range skippedGroup.FindAllStringSubmatch(output, -1) {
+				assertNotContains(t, output, "hook of "+match[1]+" is executed")
+			}
+		}
+		leaves := actual[:0]
+		for _, name := range actual {
+			if !isGroupName(name) {
+				leaves = append(leaves, name)
+			}
+		}
+		sort.Strings(leaves)
+		assertEvents(t, leaves,
+			"TestShard/big_group/test_0",
+			"TestShard/big_group/test_1",
+			"TestShard/big_group/test_2",
+			"TestShard/big_group/test_3",
+			"TestShard/big_group/test_4",
+			"TestShard/big_group/test_5",
+			"TestShard/big_group/test_6",
+			"TestShard/big_group/test_7",
+			"TestShard/big_group/test_8",
+			"TestShard/big_group/test_9",
+			"TestShard/repeated/iteration_1",
+			"TestShard/repeated/iteration_2",
+			"TestShard/repeated/iteration_3",
+			"TestShard/same_name/#00",
+			"TestShard/same_name/#01",
+			"TestShard/same_name/tab_and_nbsp/test_0",
+			"TestShard/same_name/tab_and_nbsp/test_1",
+			"TestShard/same_name/x",
+			"TestShard/same_name/x#01",
+			"TestShard/same_name/x#01#01",
+			"TestShard/small_group/test_0",
+		)
+	})
+	t.Run("should fail on invalid value", func(t *testing.T) {
+		t.Parallel()
+		output, err := runTestdata(t, "shard", "PT_SHARD=4/3")
+		if err == nil {
+			t.Fatalf("go test succeeded:\n%s", output)
+		}
+		assertContains(t, output, `invalid PT_SHARD value "4/3": expected i/n, where 1 <= i <= n`)
+	})
+}
+
+func isGroupName(name string) bool {
+	switch name {
+	case "TestShard", "TestShard/big_group", "TestShard/small_group", "TestShard/same_name",
+		"TestShard/same_name/tab_and_nbsp", "TestShard/repeated":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,126 @@
+package pt
+
+import (
+	"flag"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"os"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	// shuffleEnv is the environment variable which enables shuffle mode for all tests: on, off or a seed.
+	shuffleEnv = "PT_SHUFFLE"
+	// shuffleSeedEnv is the environment variable which sets the seed to replay the order of shuffled tests.
+	shuffleSeedEnv = "PT_SHUFFLE_SEED"
+)
+
+/*
+Shuffle is an [Option] which makes the group start its tests in random order.
+The option is inherited by nested groups.
+It is useful to find tests that depend on each other (e.g. on a global state changed by another test).
+
+	pt.With(pt.Group("uses global registry", tests...), pt.Shuffle())
+
+Shuffle mode is enabled for all tests run by pt with environment variable PT_SHUFFLE=on
+or with -shuffle flag of go test.
+Tests of [Serial] and [Sequential] are never shuffled, because their order is meaningful.
+
+The seed is printed once per package, the order can be replayed exactly with environment variable PT_SHUFFLE_SEED:
+
+	PT_SHUFFLE=on PT_SHUFFLE_SEED=1665243912 go test ./...
+
+The order of tests of a group depends only on the seed and the name of the group,
+so it is the same even if other tests of the package are filtered out with -run flag.
+*/
+func Shuffle() Option {
+	return func(target *node) {
+		target.shuffle = true
+	}
+}
+
+// shuffleConfig holds shuffle settings parsed from environment and flags.
+var shuffleConfig struct { //nolint:gochecknoglobals // environment is parsed once
+	once    sync.Once
+	enabled bool
+	seed    int64
+	printed sync.Once
+}
+
+// shuffleSettings returns whether shuffle mode is enabled for all tests and the seed.
+func shuffleSettings() (bool, int64) {
+	shuffleConfig.once.Do(func() {
+		shuffleConfig.enabled, shuffleConfig.seed = parseShuffle(os.Getenv(shuffleEnv), os.Getenv(shuffleSeedEnv), goShuffleFlag())
+	})
+	return shuffleConfig.enabled, shuffleConfig.seed
+}
+
+// goShuffleFlag returns the value of -shuffle flag of go test.
+func goShuffleFlag() string {
+	if f := flag.Lookup("test.shuffle"); f != nil {
+		return f.Value.String()
+	}
+	return ""
+}
+
+func parseShuffle(mode string, seed string, goFlag string) (bool, int64) {
+	enabled := false
+	value := time.Now().UnixNano()
+	for _, setting := range []struct{ name, value string }{{"-test.shuffle", goFlag}, {shuffleEnv, mode}} {
+		switch setting.value {
+		case "", "off":
+		case "on":
+			enabled = true
+		default:
+			n, err := strconv.ParseInt(setting.value, 10, 64)
+			if err != nil {
+				panic(fmt.Sprintf("invalid %s value %q: expected on, off or seed", setting.name, setting.value))
+			}
+			enabled = true
+			value = n
+		}
+	}
+	if mode == "off" {
+		enabled = false
+	}
+	if seed != "" {
+		n, err := strconv.ParseInt(seed, 10, 64)
+		if err != nil {
+			panic(fmt.Sprintf("invalid %s value %q: expected integer", shuffleSeedEnv, seed))
+		}
+		value = n
+	}
+	return enabled, value
+}
+
+// shuffled returns true if tests of the scope should be started in random order.
+func (s *scope) shuffled() bool {
+	if enabled, _ := shuffleSettings(); enabled {
+		return true
+	}
+	for ; s != nil; s = s.parent {
+		if s.owner != nil && s.owner.shuffle {
+			return true
+		}
+	}
+	return false
+}
+
+// shuffle changes the order of tests randomly.
+// The order depends only on the seed and the name of the scope.
+func (s *scope) shuffle(tests []testing.InternalTest) {
+	_, seed := shuffleSettings()
+	shuffleConfig.printed.Do(func() {
+		fmt.Printf("pt: shuffle seed %d, replay the order with %s=%d\n", seed, shuffleSeedEnv, seed)
+	})
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(s.t.Name()))
+	random := rand.New(rand.NewSource(seed ^ int64(hash.Sum64()))) //nolint:gosec // order of tests is not a secret
+	random.Shuffle(len(tests), func(i, j int) {
+		tests[i], tests[j] = tests[j], tests[i]
+	})
+}
@@ -0,0 +1,89 @@
+package pt_test
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestShuffle(t *testing.T) {
+	t.Parallel()
+	t.Run("should shuffle group with option", func(t *testing.T) {
+		t.Parallel()
+		output, err := runTestdata(t, "shuffle", "PT_SHUFFLE=", "PT_SHUFFLE_SEED=")
+		if err != nil {
+			t.Fatalf("go test failed: %s\n%s", err, output)
+		}
+		assertContains(t, output, "pt: shuffle seed ")
+		if order := startOrder(output, "TestShuffle/shuffled/"); isDeclarationOrder(order) {
+			t.Errorf("tests are not shuffled: %v", order)
+		}
+		if order := startOrder(output, "TestShuffle/ordered/"); !isDeclarationOrder(order) {
+			t.Errorf("tests are shuffled: %v", order)
+		}
+	})
+	t.Run("should shuffle all groups by environment", func(t *testing.T) {
+		t.Parallel()
+		output, err := runTestdata(t, "shuffle", "PT_SHUFFLE=on", "PT_SHUFFLE_SEED=")
+		if err != nil {
+			t.Fatalf("go test failed: %s\n%s", err, output)
+		}
+		if order := startOrder(output, "TestShuffle/ordered/"); isDeclarationOrder(order) {
+			t.Errorf("tests are not shuffled: %v", order)
+		}
+		if order := startOrder(output, "TestShuffle/serial/"); !isDeclarationOrder(order) {
+			t.Errorf("serial tests are shuffled: %v", order)
+		}
+	})
+	t.Run("should not shuffle groups without option when disabled", func(t *testing.T) {
+		t.Parallel()
+		output, err := runTestdata(t, "shuffle", "PT_SHUFFLE=off", "PT_SHUFFLE_SEED=")
+		if err != nil {
+			t.Fatalf("go test failed: %s\n%s", err, output)
+		}
+		if order := startOrder(output, "TestShuffle/shuffled/"); isDeclarationOrder(order) {
+			t.Errorf("option is ignored: %v", order)
+		}
+		if order := startOrder(output, "TestShuffle/ordered/"); !isDeclarationOrder(order) {
+			t.Errorf("tests are shuffled: %v", order)
+		}
+	})
+	t.Run("should replay order with seed", func(t *testing.T) {
+		t.Parallel()
+		output1, err := runTestdata(t, "shuffle", "PT_SHUFFLE=on", "PT_SHUFFLE_SEED=42")
+		if err != nil {
+			t.Fatalf("go test failed: %s\n%s", err, output1)
+		}
+		output2, err := runTestdata(t, "shuffle", "PT_SHUFFLE=42", "PT_SHUFFLE_SEED=")
+		if err != nil {
+			t.Fatalf("go test failed: %s\n%s", err, output2)
+		}
+		assertContains(t, output1, "pt: shuffle seed 42, replay the order with PT_SHUFFLE_SEED=42")
+		for _, prefix := range []string{"TestShuffle/shuffled/", "TestShuffle/ordered/"} {
+			order1 := startOrder(output1, prefix)
+			order2 := startOrder(output2, prefix)
+			if strings.Join(order1, ",") != strings.Join(order2, ",") {
+				t.Errorf("order %v != %v", order1, order2)
+			}
+		}
+	})
+}
+
+// startOrder returns names of tests with prefix in the order they are started.
+func startOrder(output string, prefix string) []string {
+	var order []string
+	for _, line := range strings.Split(output, "\n") {
+		if name := strings.TrimPrefix(line, "=== RUN   "+prefix); name != line {
+			order = append(order, name)
+		}
+	}
+	return order
+}
+
+func isDeclarationOrder(order []string) bool {
+	for i := 1; i < len(order); i++ {
+		if order[i-1] > order[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,98 @@
+package pt
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// slowestEnv is the environment variable with the number of slowest tests printed by [Main].
+const slowestEnv = "PT_SLOWEST"
+
+// slowestCount returns the number of slowest tests to print or 0 if the summary is disabled.
+func slowestCount() int {
+	value := reportSetting(slowestEnv)
+	if value == "" {
+		return 0
+	}
+	count, err := strconv.Atoi(value)
+	if err != nil || count < 1 {
+		panic(fmt.Sprintf("invalid %s value %q: expected positive integer", slowestEnv, value))
+	}
+	return count
+}
+
+// printSummary writes count slowest tests and the critical path of executed tests to w.
+// Duration of a test is the time it was running, waiting for parallel slots and resources is not included.
+func (r *reporter) printSummary(w io.Writer, count int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var leaves []*reportedTest
+	var collect func(tests []*reportedTest)
+	collect = func(tests []*reportedTest) {
+		for _, test := range tests {
+			if test.Status == "skip" || test.End.IsZero() {
+				continue
+			}
+			if len(test.Children) == 0 {
+				leaves = append(leaves, test)
+			}
+			collect(test.Children)
+		}
+	}
+	collect(r.roots)
+	if len(leaves) == 0 {
+		return
+	}
+	sort.SliceStable(leaves, func(i, j int) bool {
+		return leaves[i].duration() > leaves[j].duration()
+	})
+	if len(leaves) > count {
+		leaves = leaves[:count]
+	}
+	fmt.Fprintf(w, "\nSLOWEST: %d slowest test(s)\n", len(leaves))
+	for _, test := range leaves {
+		fmt.Fprintf(w, "SLOWEST: %8s %s\n", test.duration().Round(time.Millisecond), test.Name)
+	}
+	var root *reportedTest
+	for _, test := range r.roots {
+		if !test.End.IsZero() && (root == nil || test.duration() > root.duration()) {
+			root = test
+		}
+	}
+	if root == nil {
+		return
+	}
+	fmt.Fprintf(w, "\nCRITICAL PATH: %s\n", root.duration().Round(time.Millisecond))
+	printCriticalPath(w, root, 0)
+}
+
+// printCriticalPath writes the chain of tests which bounds the duration of test.
+// All tests of a serial group are on the chain, because they run one by one,
+// while only the test finished last is on the chain for a parallel group.
+func printCriticalPath(w io.Writer, test *reportedTest, depth int) {
+	fmt.Fprintf(w, "CRITICAL PATH: %8s %s%s (%s)\n",
+		test.duration().Round(time.Millisecond), strings.Repeat("  ", depth), test.Name, test.Kind)
+	var chain []*reportedTest
+	for _, child := range test.Children {
+		if child.Status == "skip" || child.End.IsZero() {
+			continue
+		}
+		if test.Kind == KindSerial.String() {
+			chain = append(chain, child)
+		} else if len(chain) == 0 || child.End.After(chain[0].End) {
+			chain = []*reportedTest{child}
+		}
+	}
+	for _, child := range chain {
+		printCriticalPath(w, child, depth+1)
+	}
+}
+
+// duration returns the time the test was running.
+func (t *reportedTest) duration() time.Duration {
+	return t.End.Sub(t.running)
+}
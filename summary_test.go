@@ -0,0 +1,36 @@
+package pt_test
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestSummary(t *testing.T) {
+	t.Parallel()
+	t.Run("should print slowest tests and critical path", func(t *testing.T) {
+		t.Parallel()
+		output, err := runTestdata(t, "summary", "PT_SLOWEST=2", "GOFLAGS=-parallel=4")
+		if err != nil {
+			t.Fatalf("go test failed: %s\n%s", err, output)
+		}
+		duration := regexp.MustCompile(` +[0-9.]+m?s `)
+		output = duration.ReplaceAllString(output, " D ")
+		assertContains(t, output,
+			"SLOWEST: 2 slowest test(s)\n"+
+				"SLOWEST: D TestSummary/chain/second\n"+
+				"SLOWEST: D TestSummary/chain/first\n",
+			"CRITICAL PATH: D TestSummary (Group)\n"+
+				"CRITICAL PATH: D   TestSummary/chain (Serial)\n"+
+				"CRITICAL PATH: D     TestSummary/chain/first (Test)\n"+
+				"CRITICAL PATH: D     TestSummary/chain/second (Test)\n",
+		)
+	})
+	t.Run("should not print summary by default", func(t *testing.T) {
+		t.Parallel()
+		output, err := runTestdata(t, "summary", "PT_SLOWEST=")
+		if err != nil {
+			t.Fatalf("go test failed: %s\n%s", err, output)
+		}
+		assertNotContains(t, output, "SLOWEST", "CRITICAL PATH")
+	})
+}
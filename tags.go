@@ -0,0 +1,230 @@
+package pt
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"unicode"
+)
+
+// tagsEnv is the environment variable with tag expression which selects tests to run.
+const tagsEnv = "PT_TAGS"
+
+/*
+Tags is an [Option] which labels the test with tags.
+When applied to a group, tags are inherited by each test of the group (including nested groups).
+A tag consists of letters, digits and symbols "_", "-", ".", ":".
+
+	pt.With(pt.Group("repository", tests...), pt.Tags("db", "integration"))
+	pt.Test("should import big file", testImport, pt.Tags("slow"))
+
+Tests are selected by tag expression in environment variable PT_TAGS.
+The expression consists of tags, operators "!", "&&", "||" and parentheses.
+A tag in the expression is true if the test has the tag.
+Tests not matching the expression are skipped, as well as groups without matching tests.
+
+	PT_TAGS='db && !slow' go test ./...
+*/
+func Tags(tags ...string) Option {
+	for _, tag := range tags {
+		if !isTag(tag) {
+			panic(fmt.Sprintf("invalid tag %q: only letters, digits and symbols _-.: are allowed", tag))
+		}
+	}
+	return func(target *node) {
+		target.tags = append(target.tags[:len(target.tags):len(target.tags)], tags...)
+	}
+}
+
+// tagFilter holds parsed value of PT_TAGS environment variable.
+var tagFilter struct { //nolint:gochecknoglobals // environment is parsed once
+	once  sync.Once
+	value string
+	expr  tagExpr // nil if tests are not filtered
+}
+
+// tagExpr is a parsed tag expression which reports whether a test with tags matches it.
+type tagExpr func(tags map[string]bool) bool
+
+// skipUntagged skips the test n (nil if test is not built by pt) if it does not match PT_TAGS.
+// A group is skipped if none of its tests match.
+func (s *scope) skipUntagged(t *testing.T, n *node) {
+	tagFilter.once.Do(func() {
+		tagFilter.value = os.Getenv(tagsEnv)
+		if strings.TrimSpace(tagFilter.value) != "" {
+			tagFilter.expr = parseTagExpr(tagFilter.value)
+		}
+	})
+	if tagFilter.expr == nil {
+		return
+	}
+	inherited := s.tags()
+	if n != nil && n.kind != KindTest {
+		if !n.anyMatches(tagFilter.expr, inherited) {
+			t.Skipf("skipped by %s=%q, no tests of the group match", tagsEnv, tagFilter.value)
+		}
+		return
+	}
+	if n != nil {
+		inherited = append(inherited, n.tags...)
+	}
+	if !tagFilter.expr(tagSet(inherited)) {
+		t.Skipf("skipped by %s=%q, tags of the test: %v", tagsEnv, tagFilter.value, inherited)
+	}
+}
+
+// tags returns tags of the groups the scope belongs to.
+func (s *scope) tags() []string {
+	var tags []string
+	for ; s != nil; s = s.parent {
+		if s.owner != nil {
+			tags = append(tags, s.owner.tags...)
+		}
+	}
+	return tags
+}
+
+// anyMatches returns true if at least one test of the subtree of n with inherited tags matches expr.
+func (n *node) anyMatches(expr tagExpr, inherited []string) bool {
+	tags := append(inherited[:len(inherited):len(inherited)], n.tags...)
+	switch n.kind {
+	case KindTest:
+		return expr(tagSet(tags))
+	case KindHook, KindProvide:
+		return false
+	}
+	for _, child := range n.children {
+		if c := lookup(child); c == nil {
+			if expr(tagSet(tags)) {
+				return true
+			}
+		} else if c.anyMatches(expr, tags) {
+			return true
+		}
+	}
+	return false
+}
+
+func tagSet(tags []string) map[string]bool {
+	set := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		set[tag] = true
+	}
+	return set
+}
+
+func isTag(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !isTagRune(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func isTagRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("_-.:", r)
+}
+
+// parseTagExpr parses tag expression, it panics if the expression is invalid.
+func parseTagExpr(value string) tagExpr {
+	p := tagParser{input: value}
+	p.next()
+	expr := p.parseOr()
+	if p.token != "" {
+		panic(fmt.Sprintf("invalid %s expression %q: unexpected %q", tagsEnv, value, p.token))
+	}
+	return expr
+}
+
+// tagParser is a recursive descent parser of tag expression.
+//
+//	or      = and { "||" and }
+//	and     = not { "&&" not }
+//	not     = "!" not | primary
+//	primary = tag | "(" or ")"
+type tagParser struct {
+	input string
+	pos   int
+	token string // current token, empty at the end of input
+}
+
+func (p *tagParser) next() {
+	for p.pos < len(p.input) && (p.input[p.pos] == ' ' || p.input[p.pos] == '\t') {
+		p.pos++
+	}
+	start := p.pos
+	switch {
+	case p.pos == len(p.input):
+	case strings.HasPrefix(p.input[p.pos:], "&&"), strings.HasPrefix(p.input[p.pos:], "||"):
+		p.pos += 2
+	case strings.ContainsRune("!()", rune(p.input[p.pos])):
+		p.pos++
+	default:
+		for _, r := range p.input[p.pos:] {
+			if !isTagRune(r) {
+				break
+			}
+			p.pos += len(string(r))
+		}
+		if p.pos == start {
+			panic(fmt.Sprintf("invalid %s expression %q: unexpected symbol at position %d", tagsEnv, p.input, start))
+		}
+	}
+	p.token = p.input[start:p.pos]
+}
+
+func (p *tagParser) parseOr() tagExpr {
+	left := p.parseAnd()
+	for p.token == "||" {
+		p.next()
+		l, r := left, p.parseAnd()
+		left = func(tags map[string]bool) bool { return l(tags) || r(tags) }
+	}
+	return left
+}
+
+func (p *tagParser) parseAnd() tagExpr {
+	left := p.parseNot()
+	for p.token == "&&" {
+		p.next()
+		l, r := left, p.parseNot()
+		left = func(tags map[string]bool) bool { return l(tags) && r(tags) }
+	}
+	return left
+}
+
+func (p *tagParser) parseNot() tagExpr {
+	if p.token == "!" {
+		p.next()
+		operand := p.parseNot()
+		return func(tags map[string]bool) bool { return !operand(tags) }
+	}
+	return p.parsePrimary()
+}
+
+func (p *tagParser) parsePrimary() tagExpr {
+	switch {
+	case p.token == "(":
+		p.next()
+		expr := p.parseOr()
+		if p.token != ")" {
+			panic(fmt.Sprintf("invalid %s expression %q: expected \")\"", tagsEnv, p.input))
+		}
+		p.next()
+		return expr
+	case isTag(p.token):
+		tag := p.token
+		p.next()
+		return func(tags map[string]bool) bool { return tags[tag] }
+	case p.token == "":
+		panic(fmt.Sprintf("invalid %s expression %q: unexpected end", tagsEnv, p.input))
+	default:
+		panic(fmt.Sprintf("invalid %s expression %q: unexpected %q", tagsEnv, p.input, p.token))
+	}
+}
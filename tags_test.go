@@ -0,0 +1,66 @@
+package pt_test
+
+import (
+	"testing"
+
+	"github.com/maratori/pt"
+)
+
+func TestTags(t *testing.T) {
+	t.Parallel()
+	t.Run("should panic on invalid tag", func(t *testing.T) {
+		t.Parallel()
+		defer assertPanic(t, `invalid tag "a b": only letters, digits and symbols _-.: are allowed`)
+		pt.Tags("a b")
+	})
+	t.Run("should panic on empty tag", func(t *testing.T) {
+		t.Parallel()
+		defer assertPanic(t, `invalid tag "": only letters, digits and symbols _-.: are allowed`)
+		pt.Tags("")
+	})
+	t.Run("should run all tests without expression", func(t *testing.T) {
+		t.Parallel()
+		output, err := runTestdata(t, "tags", "PT_TAGS=")
+		if err != nil {
+			t.Fatalf("go test failed: %s\n%s", err, output)
+		}
+		assertNotContains(t, output, "--- SKIP")
+	})
+	t.Run("should select tests by expression", func(t *testing.T) {
+		t.Parallel()
+		output, err := runTestdata(t, "tags", "PT_TAGS=!slow && (db || api)")
+		if err != nil {
+			t.Fatalf("go test failed: %s\n%s", err, output)
+		}
+		assertContains(t, output,
+			"--- PASS: TestTags/db/fast_query ",
+			"--- SKIP: TestTags/db/slow_query ",
+			`skipped by PT_TAGS="!slow && (db || api)", tags of the test: [db slow]`,
+			"--- SKIP: TestTags/api/fast_call ",
+			"--- SKIP: TestTags/api/slow_call ",
+			"--- PASS: TestTags/api/nested/fast_call ",
+			"--- SKIP: TestTags/untagged_raw_test ",
+			`skipped by PT_TAGS="!slow && (db || api)", no tests of the group match`,
+		)
+	})
+	t.Run("should apply group tags to raw tests", func(t *testing.T) {
+		t.Parallel()
+		output, err := runTestdata(t, "tags", "PT_TAGS=raw")
+		if err != nil {
+			t.Fatalf("go test failed: %s\n%s", err, output)
+		}
+		assertContains(t, output,
+			"--- PASS: TestTags/untagged_raw_test/raw ",
+			"--- SKIP: TestTags/db ",
+			"--- SKIP: TestTags/api ",
+		)
+	})
+	t.Run("should fail on invalid expression", func(t *testing.T) {
+		t.Parallel()
+		output, err := runTestdata(t, "tags", "PT_TAGS=db &&")
+		if err == nil {
+			t.Fatalf("go test succeeded:\n%s", output)
+		}
+		assertContains(t, output, `invalid PT_TAGS expression "db &&": unexpected end`)
+	})
+}
@@ -0,0 +1,48 @@
+package bench
+
+import (
+	"testing"
+
+	"github.com/maratori/pt"
+)
+
+type sumCase struct {
+	a, b     int
+	expected int
+}
+
+var sumTests = []testing.InternalTest{ //nolint:gochecknoglobals // shared by test, benchmark and fuzz target
+	pt.Group("sum",
+		pt.EachTB([]sumCase{{1, 2, 3}, {-4, 4, 0}},
+			func(c sumCase) string { return "case" },
+			func(tb testing.TB, c sumCase) {
+				if c.a+c.b != c.expected {
+					tb.Errorf("%d+%d != %d", c.a, c.b, c.expected)
+				}
+			},
+		),
+		pt.TB("parallel", func(tb testing.TB) {}, pt.RunParallel()),
+		pt.Test("requires T", func(t *testing.T) {}),
+		pt.XTest("pending", func(t *testing.T) {}),
+	),
+	pt.TableTB(map[string]string{"table": "abc"}, func(tb testing.TB, c string) {}),
+	pt.Group("with hooks",
+		pt.BeforeEach(func(t *testing.T) {}),
+		pt.TB("requires hook", func(tb testing.TB) {}),
+	),
+}
+
+func TestSum(t *testing.T) {
+	pt.PackageParallel(t, sumTests...)
+}
+
+func BenchmarkSum(b *testing.B) {
+	pt.Benchmark(b, sumTests...)
+}
+
+func FuzzSum(f *testing.F) {
+	pt.Seed(f, sumTests[0]) // without the table of strings
+	f.Fuzz(func(t *testing.T, a, b, expected int) {
+		t.Logf("seed %d %d %d", a, b, expected)
+	})
+}
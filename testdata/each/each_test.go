@@ -0,0 +1,32 @@
+package each
+
+import (
+	"testing"
+
+	"github.com/maratori/pt"
+)
+
+type sumCase struct {
+	a, b     int
+	expected int
+}
+
+func TestEach(t *testing.T) {
+	pt.PackageParallel(t,
+		pt.Each([]sumCase{{1, 2, 3}, {2, 2, 5}}, func(c sumCase) string { return "sum" }, func(t *testing.T, c sumCase) {
+			if c.a+c.b != c.expected {
+				t.Error("wrong sum")
+			}
+		}),
+	)
+}
+
+func TestTable(t *testing.T) {
+	pt.PackageParallel(t,
+		pt.Table(map[string]int{"ok": 1, "fails": -1}, func(t *testing.T, c int) {
+			if c < 0 {
+				t.Error("negative value")
+			}
+		}),
+	)
+}
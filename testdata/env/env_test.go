@@ -0,0 +1,49 @@
+package env
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/maratori/pt"
+)
+
+func TestNested(t *testing.T) {
+	pt.PackageParallel(t,
+		pt.Test("first", sleep, pt.Env("PT_TESTDATA_ENV", "first")),
+		pt.Test("second", sleep, pt.Env("PT_TESTDATA_ENV", "second")),
+		pt.Test("outer", func(t *testing.T) {
+			pt.Parallel(t, pt.Test("inner", sleep))
+		}),
+		pt.Test("outer of test with Env", func(t *testing.T) {
+			pt.Parallel(t, pt.Test("inner", func(t *testing.T) {
+				if os.Getenv("PT_TESTDATA_ENV") != "inner" {
+					t.Error("variable is not set in nested test")
+				}
+			}, pt.Env("PT_TESTDATA_ENV", "inner")))
+		}),
+		pt.Test("outer of wrapped test with Env", func(t *testing.T) {
+			t.Run("wrap", func(t *testing.T) {
+				pt.Parallel(t, pt.Test("inner", func(t *testing.T) {
+					if os.Getenv("PT_TESTDATA_ENV") != "wrapped" {
+						t.Error("variable is not set in wrapped test")
+					}
+				}, pt.Env("PT_TESTDATA_ENV", "wrapped")))
+			})
+		}),
+	)
+}
+
+func TestNestedInEnv(t *testing.T) {
+	pt.PackageParallel(t,
+		pt.Test("outer", func(t *testing.T) {
+			pt.Parallel(t, pt.Test("inner", func(t *testing.T) {
+				t.Error("test nested in test with Env is run")
+			}))
+		}, pt.Env("PT_TESTDATA_ENV", "outer")),
+	)
+}
+
+func sleep(*testing.T) {
+	time.Sleep(10 * time.Millisecond)
+}
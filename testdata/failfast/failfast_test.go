@@ -0,0 +1,27 @@
+package failfast
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/maratori/pt"
+)
+
+func TestFailFast(t *testing.T) {
+	pt.PackageParallel(t,
+		pt.Group("group",
+			pt.TestCtx("waiting", func(ctx context.Context, t *testing.T) {
+				select {
+				case <-ctx.Done():
+					t.Log("context is cancelled")
+				case <-time.After(time.Second):
+				}
+			}),
+			pt.Test("failed", func(t *testing.T) {
+				time.Sleep(100 * time.Millisecond)
+				t.Error("fail")
+			}),
+		),
+	)
+}
@@ -0,0 +1,42 @@
+package focus
+
+import (
+	"testing"
+
+	"github.com/maratori/pt"
+)
+
+func TestUnfocused(t *testing.T) {
+	pt.PackageParallel(t,
+		pt.Test("unfocused test", func(t *testing.T) {
+			t.Log("unfocused test is run")
+		}),
+	)
+}
+
+func TestFocused(t *testing.T) {
+	pt.PackageParallel(t,
+		pt.FTest("focused test", func(t *testing.T) {
+			t.Log("focused test is run")
+		}),
+		pt.Group("group with focused test",
+			pt.BeforeAll(func(t *testing.T) {
+				t.Log("hook of group with focused test is run")
+			}),
+			pt.FTest("nested focused test", func(t *testing.T) {}),
+		),
+		pt.FGroup("focused group",
+			pt.Test("test in focused group", func(t *testing.T) {
+				t.Log("test in focused group is run")
+			}),
+		),
+		pt.Group("group",
+			pt.BeforeAll(func(t *testing.T) {
+				t.Log("hook of unfocused group is run")
+			}),
+			pt.Test("unfocused test in group", func(t *testing.T) {
+				t.Log("unfocused test in group is run")
+			}),
+		),
+	)
+}
@@ -0,0 +1,24 @@
+package history
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/maratori/pt"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(pt.Main(m))
+}
+
+func TestHistory(t *testing.T) {
+	pt.PackageParallel(t,
+		pt.Group("group",
+			pt.Test("fast", func(t *testing.T) {}),
+			pt.Test("medium 1", func(t *testing.T) {}),
+			pt.Test("medium 2", func(t *testing.T) {}),
+			pt.Test("slow", func(t *testing.T) { time.Sleep(50 * time.Millisecond) }),
+		),
+	)
+}
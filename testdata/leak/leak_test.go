@@ -0,0 +1,35 @@
+package leak
+
+import (
+	"context"
+	"testing"
+
+	"github.com/maratori/pt"
+)
+
+func TestLeak(t *testing.T) {
+	block := make(chan struct{})
+	t.Cleanup(func() { close(block) })
+	pt.PackageParallel(t,
+		pt.With(pt.Group("group",
+			pt.Test("leaks", func(t *testing.T) {
+				go leak(block)
+			}),
+			pt.TestCtx("stops on cancel", func(ctx context.Context, t *testing.T) {
+				go waitForCancel(ctx)
+			}),
+			pt.Test("no goroutines", func(t *testing.T) {}),
+		), pt.DetectLeaks()),
+		pt.Test("not detected", func(t *testing.T) {
+			go leak(block)
+		}),
+	)
+}
+
+func leak(block chan struct{}) {
+	<-block
+}
+
+func waitForCancel(ctx context.Context) {
+	<-ctx.Done()
+}
@@ -0,0 +1,31 @@
+package limit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/maratori/pt"
+)
+
+func TestNestedGroups(t *testing.T) {
+	a := pt.Test("a", sleep)
+	b := pt.Test("b", sleep)
+	pt.PackageParallel(t, pt.With(pt.Group("outer",
+		pt.Group("g1", a, b),
+		pt.Group("g2", a, b),
+		pt.Group("g3", a, b),
+	), pt.Limit(1)))
+}
+
+func TestNestedParallel(t *testing.T) {
+	pt.PackageParallel(t, pt.With(pt.Group("limited",
+		pt.Test("outer", func(t *testing.T) {
+			pt.Parallel(t, pt.Test("a", sleep), pt.Test("b", sleep))
+		}),
+		pt.Test("c", sleep),
+	), pt.Limit(1)))
+}
+
+func sleep(*testing.T) {
+	time.Sleep(10 * time.Millisecond)
+}
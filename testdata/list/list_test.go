@@ -0,0 +1,20 @@
+package list
+
+import (
+	"testing"
+
+	"github.com/maratori/pt"
+)
+
+func TestList(t *testing.T) {
+	pt.PackageParallel(t,
+		pt.BeforeAll(func(t *testing.T) { t.Fatal("hook is executed") }),
+		pt.Group("group",
+			pt.Test("same name", func(t *testing.T) { t.Fatal("test is executed") }),
+			pt.Test("same name", func(t *testing.T) { t.Fatal("test is executed") }, pt.Tags("slow")),
+			pt.Each([]int{1, 2}, nil, func(t *testing.T, c int) { t.Fatal("case is executed") }),
+		),
+		pt.Repeat(2, pt.Test("repeated", func(t *testing.T) { t.Fatal("test is executed") })),
+		testing.InternalTest{Name: "raw", F: func(t *testing.T) { t.Fatal("test is executed") }},
+	)
+}
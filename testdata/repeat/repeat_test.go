@@ -0,0 +1,35 @@
+package repeat
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/maratori/pt"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(pt.Main(m))
+}
+
+func TestStress(t *testing.T) {
+	pt.PackageParallel(t,
+		pt.Group("group",
+			pt.Test("stressed test", func(t *testing.T) {}),
+			pt.Test("other test", func(t *testing.T) {}),
+		),
+	)
+}
+
+func TestRepeatFails(t *testing.T) {
+	if os.Getenv("PT_TESTDATA_FAIL") == "" {
+		t.Skip()
+	}
+	pt.PackageParallel(t,
+		pt.Repeat(4, pt.Test("flaky", func(t *testing.T) {
+			if strings.HasSuffix(t.Name(), "2") || strings.HasSuffix(t.Name(), "4") {
+				t.Errorf("iteration %s failed", t.Name()[len(t.Name())-1:])
+			}
+		})),
+	)
+}
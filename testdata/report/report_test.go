@@ -0,0 +1,32 @@
+package report
+
+import (
+	"os"
+	"testing"
+
+	"github.com/maratori/pt"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(pt.Main(m))
+}
+
+func TestReport(t *testing.T) {
+	pt.PackageParallel(t,
+		pt.With(pt.Group("group",
+			pt.Test("passed", func(t *testing.T) {
+				t.Log("first line")
+				t.Log("second line")
+			}),
+			pt.Test("failed", func(t *testing.T) {
+				t.Error("failure message")
+			}),
+			pt.XTest("skipped", func(t *testing.T) {}),
+		), pt.Tags("tag")),
+		pt.Test("flaky", func(t *testing.T) {
+			if os.Getenv("PT_ATTEMPT") == "" {
+				t.Fatal("first attempt failed")
+			}
+		}, pt.Retry(3, 0)),
+	)
+}
@@ -0,0 +1,58 @@
+package resource
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/maratori/pt"
+)
+
+func TestNested(t *testing.T) {
+	pt.PackageParallel(t,
+		pt.Test("outer", func(t *testing.T) {
+			pt.Parallel(t, pt.Test("inner", func(t *testing.T) {
+				t.Error("nested test is run")
+			}))
+		}, pt.Shared("file")),
+		pt.Test("other", func(t *testing.T) {}, pt.Exclusive("file")),
+	)
+}
+
+func TestNestedInSubtest(t *testing.T) {
+	pt.PackageParallel(t,
+		pt.Test("outer", func(t *testing.T) {
+			t.Run("wrap", func(t *testing.T) {
+				pt.Parallel(t, pt.Test("inner", func(t *testing.T) {
+					t.Error("nested test is run")
+				}, pt.Exclusive("file")))
+			})
+		}, pt.Exclusive("file")),
+	)
+}
+
+func TestExclusivePriority(t *testing.T) {
+	exclusiveStarted := make(chan struct{})
+	sharedStarted := make(chan struct{})
+	var exclusiveDone atomic.Bool
+	pt.PackageParallel(t,
+		pt.Test("first shared", func(t *testing.T) {
+			<-exclusiveStarted
+			time.Sleep(100 * time.Millisecond) // exclusive test is waiting for the resource
+			close(sharedStarted)
+			time.Sleep(100 * time.Millisecond) // second shared test is waiting too
+		}, pt.Shared("db")),
+		pt.Group("exclusive group",
+			pt.BeforeAll(func(*testing.T) { close(exclusiveStarted) }),
+			pt.Test("exclusive", func(t *testing.T) { exclusiveDone.Store(true) }, pt.Exclusive("db")),
+		),
+		pt.Group("shared group",
+			pt.BeforeAll(func(*testing.T) { <-sharedStarted }),
+			pt.Test("second shared", func(t *testing.T) {
+				if !exclusiveDone.Load() {
+					t.Error("shared claim overtook waiting exclusive one")
+				}
+			}, pt.Shared("db")),
+		),
+	)
+}
@@ -0,0 +1,79 @@
+package retry
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/maratori/pt"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(pt.Main(m))
+}
+
+func TestRetry(t *testing.T) {
+	pt.PackageParallel(t,
+		pt.Test("fails on first attempt", func(t *testing.T) {
+			if os.Getenv("PT_ATTEMPT") == "" {
+				t.Fatal("first attempt failed")
+			}
+		}, pt.Retry(3, 10*time.Millisecond)),
+		pt.With(pt.Group("group",
+			pt.Test("passes on last attempt", func(t *testing.T) {
+				if os.Getenv("PT_ATTEMPT") != "3" {
+					t.Fatalf("attempt %q failed", os.Getenv("PT_ATTEMPT"))
+				}
+			}),
+		), pt.Retry(3, 0)),
+		pt.Test("passes on first attempt", func(t *testing.T) {
+			t.Logf("attempt %q passed", os.Getenv("PT_ATTEMPT"))
+		}, pt.Retry(3, 0)),
+	)
+}
+
+func TestAlwaysFails(t *testing.T) {
+	if os.Getenv("PT_TESTDATA_FAIL") != "always" {
+		t.Skip()
+	}
+	pt.PackageParallel(t,
+		pt.Test("always fails", func(t *testing.T) {
+			t.Fatal("always failed")
+		}, pt.Retry(2, 0)),
+	)
+}
+
+func TestNotRetried(t *testing.T) {
+	if os.Getenv("PT_TESTDATA_FAIL") != "not retried" {
+		t.Skip()
+	}
+	pt.PackageParallel(t,
+		pt.Test("flaky", func(t *testing.T) {
+			if os.Getenv("PT_ATTEMPT") == "" {
+				t.Fatal("first attempt failed")
+			}
+		}, pt.Retry(2, 0)),
+		pt.Test("not retried", func(t *testing.T) {
+			if os.Getenv("PT_ATTEMPT") != "" {
+				t.Fatal("test without retry is run in attempt")
+			}
+			t.Fatal("failed without retry")
+		}),
+	)
+}
+
+func TestFlags(t *testing.T) {
+	if !testing.Short() {
+		t.Skip()
+	}
+	pt.PackageParallel(t,
+		pt.Test("gets flags", func(t *testing.T) {
+			if os.Getenv("PT_ATTEMPT") == "" {
+				t.Fatal("first attempt failed")
+			}
+			if !testing.Short() {
+				t.Fatal("flags are not forwarded to attempt")
+			}
+		}, pt.Retry(2, 0)),
+	)
+}
@@ -0,0 +1,34 @@
+package shard
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/maratori/pt"
+)
+
+func tests(count int) []testing.InternalTest {
+	result := []testing.InternalTest{
+		pt.BeforeAll(func(t *testing.T) { t.Log("hook of", t.Name(), "is executed") }),
+	}
+	for i := 0; i < count; i++ {
+		result = append(result, pt.Test(fmt.Sprintf("test %d", i), func(t *testing.T) {}))
+	}
+	return result
+}
+
+func TestShard(t *testing.T) {
+	pt.PackageParallel(t,
+		pt.Group("big group", tests(10)...),
+		pt.Group("small group", tests(1)...),
+		pt.Group("same name",
+			pt.Test("", func(t *testing.T) {}),
+			pt.Test("", func(t *testing.T) {}),
+			pt.Test("x", func(t *testing.T) {}),
+			pt.Test("x", func(t *testing.T) {}),
+			pt.Test("x#01", func(t *testing.T) {}),
+			pt.Group("tab\tand nbsp", tests(2)...),
+		),
+		pt.Repeat(3, pt.Test("repeated", func(t *testing.T) {})),
+	)
+}
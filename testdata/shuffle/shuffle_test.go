@@ -0,0 +1,24 @@
+package shuffle
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/maratori/pt"
+)
+
+func tests() []testing.InternalTest {
+	result := make([]testing.InternalTest, 0, 10)
+	for i := 0; i < 10; i++ {
+		result = append(result, pt.Test(fmt.Sprintf("test %d", i), func(t *testing.T) {}))
+	}
+	return result
+}
+
+func TestShuffle(t *testing.T) {
+	pt.PackageParallel(t,
+		pt.With(pt.Group("shuffled", tests()...), pt.Shuffle()),
+		pt.Group("ordered", tests()...),
+		pt.Serial("serial", tests()...),
+	)
+}
@@ -0,0 +1,32 @@
+package summary
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/maratori/pt"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(pt.Main(m))
+}
+
+func sleep(d time.Duration) func(t *testing.T) {
+	return func(t *testing.T) {
+		time.Sleep(d)
+	}
+}
+
+func TestSummary(t *testing.T) {
+	pt.PackageParallel(t,
+		pt.Serial("chain",
+			pt.Test("first", sleep(100*time.Millisecond)),
+			pt.Test("second", sleep(200*time.Millisecond)),
+		),
+		pt.Group("group",
+			pt.Test("fast", sleep(10*time.Millisecond)),
+			pt.Test("medium", sleep(50*time.Millisecond)),
+		),
+	)
+}
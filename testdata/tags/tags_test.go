@@ -0,0 +1,22 @@
+package tags
+
+import (
+	"testing"
+
+	"github.com/maratori/pt"
+)
+
+func TestTags(t *testing.T) {
+	pt.PackageParallel(t,
+		pt.With(pt.Group("db",
+			pt.Test("fast query", func(t *testing.T) {}),
+			pt.Test("slow query", func(t *testing.T) {}, pt.Tags("slow")),
+		), pt.Tags("db")),
+		pt.Group("api",
+			pt.Test("fast call", func(t *testing.T) {}),
+			pt.Test("slow call", func(t *testing.T) {}, pt.Tags("slow")),
+			pt.With(pt.Group("nested", pt.Test("fast call", func(t *testing.T) {})), pt.Tags("api")),
+		),
+		pt.With(pt.Group("untagged raw test", testing.InternalTest{Name: "raw", F: func(t *testing.T) {}}), pt.Tags("raw")),
+	)
+}
@@ -0,0 +1,37 @@
+package timeout
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/maratori/pt"
+)
+
+func TestTimeout(t *testing.T) {
+	pt.PackageParallel(t,
+		pt.With(pt.Group("group",
+			pt.Test("slow", func(t *testing.T) {
+				done := make(chan struct{})
+				go waitForCancel(pt.Context(t), done)
+				<-done
+			}),
+			pt.TestCtx("slow ctx", func(ctx context.Context, t *testing.T) {
+				<-ctx.Done()
+			}),
+			pt.Test("hung", func(t *testing.T) {
+				hang()
+			}),
+			pt.Test("fast", func(t *testing.T) {}),
+		), pt.Timeout(100*time.Millisecond)),
+	)
+}
+
+func hang() {
+	time.Sleep(time.Hour)
+}
+
+func waitForCancel(ctx context.Context, done chan struct{}) {
+	<-ctx.Done()
+	close(done)
+}
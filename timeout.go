@@ -0,0 +1,171 @@
+package pt
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"runtime/debug"
+	"runtime/pprof"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testLabel is the key of the pprof label which holds the name of the test run by pt.
+// Goroutines started by the test inherit the label.
+const testLabel = "pt.test"
+
+/*
+Timeout is an [Option] which limits the duration of the test including BeforeEach and AfterEach hooks.
+When the test exceeds its timeout, it fails, its context (see [Context]) is cancelled,
+and stacks of goroutines started by the test are printed to stderr.
+BeforeEach hooks and the body of the test run in a separate goroutine, so that a hung test is stopped
+at the deadline with [testing.T.FailNow] and its group and other tests are not blocked.
+The hung goroutine is left running, it should return as soon as the context is cancelled.
+
+When applied to a group, each test of the group (including nested groups) gets the timeout,
+unless the test or a nested group has its own one.
+
+	pt.With(pt.Group("calls external service", tests...), pt.Timeout(5*time.Second))
+*/
+func Timeout(d time.Duration) Option {
+	if d <= 0 {
+		panic("argument d must be positive")
+	}
+	return func(target *node) {
+		target.timeout = d
+	}
+}
+
+// Context returns the context of the test.
+// The context is cancelled when the test is finished or exceeds its [Timeout].
+// If the test has a timeout, the context has the corresponding deadline.
+// The context of a test run by pt is derived from the context of its group (see [TestCtx]).
+func Context(t *testing.T) context.Context {
+	if t == nil {
+		panic("argument t *testing.T can not be nil")
+	}
+	if value, ok := contexts.Load(t); ok {
+		if ctx, isContext := value.(context.Context); isContext {
+			return ctx
+		}
+	}
+	return newContext(t, 0)
+}
+
+// contexts maps tests to their contexts.
+var contexts sync.Map //nolint:gochecknoglobals // tests are run concurrently
+
+// newContext creates the context of t labeled with the test name.
+// The context is derived from the context of the group t belongs to (see [parentContext]).
+// If timeout is positive, t fails when it exceeds the timeout.
+func newContext(t *testing.T, timeout time.Duration) context.Context {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(parentContext(t), timeout)
+	} else {
+		ctx, cancel = context.WithCancel(parentContext(t))
+	}
+	ctx = pprof.WithLabels(ctx, pprof.Labels(testLabel, t.Name()))
+	contexts.Store(t, ctx)
+	t.Cleanup(func() {
+		failFast(t)
+		cancel()
+		contexts.Delete(t)
+	})
+	return ctx
+}
+
+// watchTimeout fails t if it is not finished by the deadline of ctx.
+// The returned channel is closed when t is failed because of the timeout.
+func watchTimeout(t *testing.T, ctx context.Context, timeout time.Duration) <-chan struct{} {
+	var mu sync.Mutex
+	finished := false
+	expired := make(chan struct{})
+	expire := func() {
+		fmt.Fprintf(os.Stderr, "pt: test %s timed out after %s, its goroutines:\n\n%s", t.Name(), timeout, goroutinesOf(t.Name()))
+		t.Errorf("test timed out after %s", timeout)
+		close(expired)
+	}
+	deadline, _ := ctx.Deadline()
+	timer := time.AfterFunc(time.Until(deadline), func() {
+		mu.Lock()
+		defer mu.Unlock()
+		if !finished {
+			finished = true
+			expire()
+		}
+	})
+	t.Cleanup(func() {
+		mu.Lock()
+		defer mu.Unlock()
+		timer.Stop()
+		// the test may return right at the deadline, when its context is cancelled, before the timer fires
+		if !finished && !time.Now().Before(deadline) {
+			expire()
+		}
+		finished = true
+	})
+	return expired
+}
+
+// runUntil runs f in a separate goroutine and stops t with [testing.T.FailNow] when expired is closed,
+// the goroutine of f is left running. If expired is nil, f is run in the current goroutine.
+func runUntil(t *testing.T, expired <-chan struct{}, f func()) {
+	if expired == nil {
+		f()
+		return
+	}
+	done := make(chan struct{})
+	var panicked any
+	var stack []byte
+	go func() {
+		defer close(done)
+		defer func() {
+			if r := recover(); r != nil {
+				panicked, stack = r, debug.Stack()
+			}
+		}()
+		f()
+	}()
+	select {
+	case <-done:
+		if panicked != nil {
+			t.Logf("test panicked: %v\n\n%s", panicked, stack)
+			panic(panicked)
+		}
+	case <-expired:
+		t.FailNow()
+	}
+}
+
+// goroutinesOf returns stacks of goroutines labeled with the test name.
+func goroutinesOf(name string) string {
+	var buf bytes.Buffer
+	_ = pprof.Lookup("goroutine").WriteTo(&buf, 1)
+	label := fmt.Sprintf("%q:%q", testLabel, name)
+	var result strings.Builder
+	for _, record := range strings.Split(buf.String(), "\n\n") {
+		if strings.Contains(record, label) {
+			result.WriteString(record)
+			result.WriteString("\n\n")
+		}
+	}
+	return result.String()
+}
+
+// timeoutOf returns the timeout of test n or of the closest group it belongs to.
+func (s *scope) timeoutOf(n *node) time.Duration {
+	if n != nil && n.timeout > 0 {
+		return n.timeout
+	}
+	for ; s != nil; s = s.parent {
+		if s.owner != nil && s.owner.timeout > 0 {
+			return s.owner.timeout
+		}
+	}
+	return 0
+}
@@ -0,0 +1,115 @@
+package pt_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/maratori/pt"
+)
+
+func TestTimeout(t *testing.T) {
+	t.Parallel()
+	t.Run("should panic on non-positive timeout", func(t *testing.T) {
+		t.Parallel()
+		defer assertPanic(t, "argument d must be positive")
+		pt.Timeout(0)
+	})
+	t.Run("should set deadline of context", func(t *testing.T) {
+		t.Parallel()
+		var timeout, groupTimeout, overriddenTimeout time.Duration
+		t.Run("internal", func(it *testing.T) {
+			pt.Parallel(it,
+				pt.Test("", func(t *testing.T) {
+					timeout = timeLeft(pt.Context(t))
+				}, pt.Timeout(time.Minute)),
+				pt.With(pt.Group("",
+					pt.Test("", func(t *testing.T) {
+						groupTimeout = timeLeft(pt.Context(t))
+					}),
+					pt.Test("", func(t *testing.T) {
+						overriddenTimeout = timeLeft(pt.Context(t))
+					}, pt.Timeout(2*time.Hour)),
+				), pt.Timeout(time.Hour)),
+			)
+		})
+		assertTimeout(t, timeout, time.Minute)
+		assertTimeout(t, groupTimeout, time.Hour)
+		assertTimeout(t, overriddenTimeout, 2*time.Hour)
+	})
+	t.Run("should fail slow test", func(t *testing.T) {
+		t.Parallel()
+		output, err := runTestdata(t, "timeout", "GOFLAGS=-timeout=1m")
+		if err == nil {
+			t.Fatalf("go test succeeded:\n%s", output)
+		}
+		assertContains(t, output,
+			"pt: test TestTimeout/group/hung timed out after 100ms, its goroutines:",
+			"timeout.hang",
+			"test timed out after 100ms",
+			"--- FAIL: TestTimeout/group/slow ",
+			"--- FAIL: TestTimeout/group/slow_ctx ",
+			"--- FAIL: TestTimeout/group/hung ",
+			"--- PASS: TestTimeout/group/fast ",
+			"--- FAIL: TestTimeout/group ",
+		)
+		assertNotContains(t, output, "panic: test timed out")
+	})
+}
+
+func TestContext(t *testing.T) {
+	t.Parallel()
+	t.Run("should panic on nil T", func(t *testing.T) {
+		t.Parallel()
+		defer assertPanic(t, "argument t *testing.T can not be nil")
+		pt.Context(nil)
+	})
+	t.Run("should cancel context after test", func(t *testing.T) {
+		t.Parallel()
+		var ctx context.Context
+		t.Run("internal", func(it *testing.T) {
+			pt.Parallel(it, pt.Test("", func(t *testing.T) {
+				ctx = pt.Context(t)
+				if ctx.Err() != nil {
+					t.Error("context is cancelled during test")
+				}
+				if _, ok := ctx.Deadline(); ok {
+					t.Error("context has deadline")
+				}
+			}))
+		})
+		if ctx.Err() == nil {
+			t.Error("context is not cancelled")
+		}
+	})
+	t.Run("should return the same context", func(t *testing.T) {
+		t.Parallel()
+		if pt.Context(t) != pt.Context(t) {
+			t.Error("contexts are different")
+		}
+	})
+	t.Run("should work for tests not run by pt", func(t *testing.T) {
+		t.Parallel()
+		var ctx context.Context
+		t.Run("internal", func(it *testing.T) {
+			ctx = pt.Context(it)
+		})
+		if ctx.Err() == nil {
+			t.Error("context is not cancelled")
+		}
+	})
+}
+
+func timeLeft(ctx context.Context) time.Duration {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return 0
+	}
+	return time.Until(deadline)
+}
+
+func assertTimeout(t *testing.T, actual time.Duration, expected time.Duration) {
+	if actual > expected || actual < expected-time.Second {
+		t.Errorf("time left %s is not close to %s", actual, expected)
+	}
+}
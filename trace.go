@@ -0,0 +1,115 @@
+package pt
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// traceEnv is the environment variable with the path of Chrome trace written by [Main].
+const traceEnv = "PT_TRACE"
+
+const (
+	tracePIDTests  = 1 // process in the trace with tests
+	tracePIDGroups = 2 // process in the trace with groups and other tests with subtests
+)
+
+// traceEvent is an event of Chrome Trace Event Format.
+type traceEvent struct {
+	Name  string         `json:"name"`
+	Cat   string         `json:"cat,omitempty"`
+	Phase string         `json:"ph"`
+	TS    int64          `json:"ts"`
+	Dur   int64          `json:"dur"`
+	PID   int            `json:"pid"`
+	TID   int            `json:"tid"`
+	Args  map[string]any `json:"args,omitempty"`
+}
+
+type trace struct {
+	TraceEvents     []traceEvent `json:"traceEvents"`
+	DisplayTimeUnit string       `json:"displayTimeUnit"`
+}
+
+// writeTrace writes Chrome trace of executed tests to path atomically.
+// Every test is a complete event from the moment it started running (after waiting for parallel slots and resources)
+// till the moment it finished. Tests without subtests are placed in process "tests",
+// others are placed in process "groups". Each lane (thread) of a process contains tests which do not overlap,
+// so the number of lanes is the max number of tests running at the same time.
+func (r *reporter) writeTrace(path string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var tests, groups []*reportedTest
+	var collect func(list []*reportedTest)
+	collect = func(list []*reportedTest) {
+		for _, test := range list {
+			if test.Status == "skip" || test.End.IsZero() {
+				continue
+			}
+			if len(test.Children) == 0 {
+				tests = append(tests, test)
+			} else {
+				groups = append(groups, test)
+			}
+			collect(test.Children)
+		}
+	}
+	collect(r.roots)
+	result := trace{
+		TraceEvents: []traceEvent{
+			{Name: "process_name", Phase: "M", PID: tracePIDTests, Args: map[string]any{"name": "tests"}},
+			{Name: "process_name", Phase: "M", PID: tracePIDGroups, Args: map[string]any{"name": "groups"}},
+		},
+		DisplayTimeUnit: "ms",
+	}
+	result.TraceEvents = append(result.TraceEvents, r.traceEvents(tracePIDTests, tests)...)
+	result.TraceEvents = append(result.TraceEvents, r.traceEvents(tracePIDGroups, groups)...)
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomically(path, data)
+}
+
+// traceEvents returns complete events of tests placed in lanes, so that tests of a lane do not overlap.
+func (r *reporter) traceEvents(pid int, tests []*reportedTest) []traceEvent {
+	sort.SliceStable(tests, func(i, j int) bool {
+		return tests[i].running.Before(tests[j].running)
+	})
+	var lanes []time.Time // end of the last test of every lane
+	events := make([]traceEvent, 0, len(tests))
+	for _, test := range tests {
+		lane := -1
+		for i, end := range lanes {
+			if !end.After(test.running) {
+				lane = i
+				break
+			}
+		}
+		if lane < 0 {
+			lane = len(lanes)
+			lanes = append(lanes, time.Time{})
+		}
+		lanes[lane] = test.End
+		args := map[string]any{
+			"status":   test.Status,
+			"attempts": test.Attempts,
+			"waited":   test.running.Sub(test.Start).String(),
+		}
+		if test.File != "" {
+			args["source"] = fmt.Sprintf("%s:%d", test.File, test.Line)
+		}
+		events = append(events, traceEvent{
+			Name:  test.Name,
+			Cat:   test.Kind,
+			Phase: "X",
+			TS:    test.running.Sub(r.start).Microseconds(),
+			Dur:   test.End.Sub(test.running).Microseconds(),
+			PID:   pid,
+			TID:   lane + 1,
+			Args:  args,
+		})
+	}
+	return events
+}
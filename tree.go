@@ -0,0 +1,179 @@
+package pt
+
+import (
+	"errors"
+	"go/token"
+	"sort"
+	"testing"
+	"time"
+)
+
+/*
+Node is a read-only view of a declared [testing.InternalTest].
+Constructors of pt return plain [testing.InternalTest] for compatibility,
+use [NodeOf] to inspect the structure of the tree before running it and [Node.InternalTest] to convert it back.
+
+	tests := []testing.InternalTest{
+		pt.Group("user", pt.Test("should be created", testCreate)),
+	}
+	_ = pt.Walk(tests, func(path []pt.Node, node pt.Node) error {
+		fmt.Println(len(path), node.Kind(), node.Name(), node.Position())
+		return nil
+	})
+
+An arbitrary [testing.InternalTest] not built by pt is a leaf of [KindTest] with zero position and options.
+*/
+type Node struct {
+	test testing.InternalTest
+	n    *node // nil if test is not built by pt
+}
+
+// NodeOptions are settings of a [Node] made by options (see [Option]).
+// Settings of a group are inherited by its tests, but NodeOptions contain only settings of the node itself.
+type NodeOptions struct {
+	Limit         int               // see [Limit]
+	Timeout       time.Duration     // see [Timeout]
+	RetryAttempts int               // see [Retry]
+	RetryBackoff  time.Duration     // see [Retry]
+	Shared        []string          // sorted resources claimed in shared mode, see [Shared]
+	Exclusive     []string          // sorted resources claimed in exclusive mode, see [Exclusive]
+	Tags          []string          // see [Tags]
+	Shuffle       bool              // see [Shuffle]
+	RunParallel   bool              // see [RunParallel]
+	DetectLeaks   bool              // see [DetectLeaks]
+	Env           map[string]string // see [Env]
+	Focused       bool              // see [FTest] and [FGroup]
+	Pending       bool              // see [XTest] and [XGroup]
+}
+
+// NodeOf returns [Node] for test.
+func NodeOf(test testing.InternalTest) Node {
+	return Node{
+		test: test,
+		n:    lookup(test),
+	}
+}
+
+// InternalTest returns the test the node is made of.
+func (n Node) InternalTest() testing.InternalTest {
+	return n.test
+}
+
+// Name returns the name of the node as it was declared.
+// The name of a hook is the name of its constructor (e.g. "BeforeEach"),
+// the name of [KindCases] node is empty, because its tests are added directly to the enclosing group.
+func (n Node) Name() string {
+	return n.test.Name
+}
+
+// Kind returns the kind of the node.
+func (n Node) Kind() Kind {
+	if n.n == nil {
+		return KindTest
+	}
+	return n.n.kind
+}
+
+// Children returns nodes declared inside of the node (including hooks and Provide directives) in declaration order.
+func (n Node) Children() []Node {
+	if n.n == nil || len(n.n.children) == 0 {
+		return nil
+	}
+	children := make([]Node, 0, len(n.n.children))
+	for _, child := range n.n.children {
+		children = append(children, NodeOf(child))
+	}
+	return children
+}
+
+// Position returns the position in source code where the node is declared.
+// It is the position of the call of constructor outside of pt (e.g. [Test], [Group] or [With] for arbitrary test).
+func (n Node) Position() token.Position {
+	if n.n == nil {
+		return token.Position{}
+	}
+	return n.n.pos
+}
+
+// Options returns settings of the node.
+func (n Node) Options() NodeOptions {
+	if n.n == nil {
+		return NodeOptions{}
+	}
+	options := NodeOptions{
+		Limit:       n.n.limit,
+		Timeout:     n.n.timeout,
+		Tags:        append([]string(nil), n.n.tags...),
+		Shuffle:     n.n.shuffle,
+		RunParallel: n.n.runParallel,
+		DetectLeaks: n.n.detectLeaks,
+		Focused:     n.n.focused,
+		Pending:     n.n.pending,
+	}
+	if len(n.n.env) > 0 {
+		options.Env = make(map[string]string, len(n.n.env))
+		for key, value := range n.n.env {
+			options.Env[key] = value
+		}
+	}
+	if n.n.retry != nil {
+		options.RetryAttempts = n.n.retry.attempts
+		options.RetryBackoff = n.n.retry.backoff
+	}
+	for resource, exclusive := range n.n.resources {
+		if exclusive {
+			options.Exclusive = append(options.Exclusive, resource)
+		} else {
+			options.Shared = append(options.Shared, resource)
+		}
+	}
+	sort.Strings(options.Shared)
+	sort.Strings(options.Exclusive)
+	return options
+}
+
+// Repeat returns the number of iterations of [KindRepeat] node or 0 for other kinds.
+func (n Node) Repeat() int {
+	if n.n == nil {
+		return 0
+	}
+	return n.n.repeat
+}
+
+// WalkFunc is the type of the function called by [Walk] for each node.
+// The path contains ancestors of the node from the outermost one, it can be retained.
+// If the function returns [ErrSkipChildren], children of the node are not visited.
+// Any other non-nil error stops the walk and is returned by [Walk].
+type WalkFunc func(path []Node, node Node) error
+
+// ErrSkipChildren is used as a return value of [WalkFunc] to skip children of the node.
+var ErrSkipChildren = errors.New("skip children") //nolint:gochecknoglobals // sentinel error like fs.SkipDir
+
+// Walk visits tests and all their descendants in declaration order, a node is visited before its children.
+func Walk(tests []testing.InternalTest, fn WalkFunc) error {
+	if fn == nil {
+		panic("argument fn WalkFunc can not be nil")
+	}
+	for _, test := range tests {
+		if err := walk(nil, NodeOf(test), fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func walk(path []Node, node Node, fn WalkFunc) error {
+	if err := fn(path, node); err != nil {
+		if errors.Is(err, ErrSkipChildren) {
+			return nil
+		}
+		return err
+	}
+	path = append(path[:len(path):len(path)], node)
+	for _, child := range node.Children() {
+		if err := walk(path, child, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
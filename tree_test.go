@@ -0,0 +1,142 @@
+package pt_test
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/maratori/pt"
+)
+
+func TestNodeOf(t *testing.T) {
+	t.Parallel()
+	t.Run("should describe test", func(t *testing.T) {
+		t.Parallel()
+		_, file, line, _ := runtime.Caller(0)
+		node := pt.NodeOf(pt.Test("test", func(*testing.T) {}, pt.Limit(2), pt.Timeout(time.Second), pt.Tags("a", "b")))
+		if node.Name() != "test" {
+			t.Errorf("name %q != %q", node.Name(), "test")
+		}
+		if node.Kind() != pt.KindTest {
+			t.Errorf("kind %s != %s", node.Kind(), pt.KindTest)
+		}
+		if node.Children() != nil {
+			t.Errorf("test has children %v", node.Children())
+		}
+		if pos := node.Position(); pos.Filename != file || pos.Line != line+1 {
+			t.Errorf("position %s != %s:%d", pos, file, line+1)
+		}
+		options := node.Options()
+		if options.Limit != 2 || options.Timeout != time.Second || strings.Join(options.Tags, ",") != "a,b" {
+			t.Errorf("wrong options %+v", options)
+		}
+	})
+	t.Run("should describe group", func(t *testing.T) {
+		t.Parallel()
+		_, file, line, _ := runtime.Caller(0)
+		node := pt.NodeOf(pt.With(pt.Serial("group",
+			pt.BeforeEach(func(*testing.T) {}),
+			pt.Test("test", func(*testing.T) {}),
+			pt.Repeat(3, pt.Test("repeated", func(*testing.T) {})),
+		), pt.Exclusive("y", "x"), pt.Shared("z"), pt.Retry(2, time.Millisecond), pt.RunParallel(), pt.DetectLeaks()))
+		if node.Kind() != pt.KindSerial {
+			t.Errorf("kind %s != %s", node.Kind(), pt.KindSerial)
+		}
+		if pos := node.Position(); pos.Filename != file || pos.Line != line+1 {
+			t.Errorf("position %s != %s:%d", pos, file, line+1)
+		}
+		options := node.Options()
+		if fmt.Sprint(options.Exclusive, options.Shared, options.RetryAttempts, options.RetryBackoff, options.RunParallel, options.DetectLeaks) != "[x y] [z] 2 1ms true true" {
+			t.Errorf("wrong options %+v", options)
+		}
+		children := node.Children()
+		var actual []string
+		for _, child := range children {
+			actual = append(actual, child.Kind().String()+" "+child.Name())
+		}
+		assertEvents(t, actual, "Hook BeforeEach", "Test test", "Repeat repeated")
+		if children[2].Repeat() != 3 || children[2].Children()[0].Name() != "repeated" {
+			t.Error("wrong repeat node")
+		}
+		if pos := children[1].Position(); pos.Line != line+3 {
+			t.Errorf("position %s != %s:%d", pos, file, line+3)
+		}
+	})
+	t.Run("should describe arbitrary test", func(t *testing.T) {
+		t.Parallel()
+		test := testing.InternalTest{Name: "raw", F: func(*testing.T) {}}
+		node := pt.NodeOf(test)
+		if node.Kind() != pt.KindTest || node.Name() != "raw" || node.Children() != nil || node.Position().Line != 0 {
+			t.Error("wrong node of arbitrary test")
+		}
+		if node.InternalTest().Name != test.Name {
+			t.Error("wrong internal test")
+		}
+	})
+}
+
+func TestWalk(t *testing.T) {
+	t.Parallel()
+	tests := []testing.InternalTest{
+		pt.Group("a",
+			pt.Test("b", func(*testing.T) {}),
+			pt.Group("c", pt.Test("d", func(*testing.T) {})),
+		),
+		pt.Group("e", pt.Test("f", func(*testing.T) {})),
+	}
+	t.Run("should panic on nil fn", func(t *testing.T) {
+		t.Parallel()
+		defer assertPanic(t, "argument fn WalkFunc can not be nil")
+		_ = pt.Walk(tests, nil)
+	})
+	t.Run("should visit all nodes in declaration order", func(t *testing.T) {
+		t.Parallel()
+		var actual []string
+		err := pt.Walk(tests, func(path []pt.Node, node pt.Node) error {
+			names := make([]string, 0, len(path)+1)
+			for _, n := range append(path, node) {
+				names = append(names, n.Name())
+			}
+			actual = append(actual, strings.Join(names, "/"))
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEvents(t, actual, "a", "a/b", "a/c", "a/c/d", "e", "e/f")
+	})
+	t.Run("should skip children", func(t *testing.T) {
+		t.Parallel()
+		var actual []string
+		err := pt.Walk(tests, func(path []pt.Node, node pt.Node) error {
+			actual = append(actual, node.Name())
+			if node.Name() == "a" {
+				return pt.ErrSkipChildren
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEvents(t, actual, "a", "e", "f")
+	})
+	t.Run("should stop on error", func(t *testing.T) {
+		t.Parallel()
+		expected := errors.New("stop")
+		var actual []string
+		err := pt.Walk(tests, func(path []pt.Node, node pt.Node) error {
+			actual = append(actual, node.Name())
+			if node.Name() == "c" {
+				return expected
+			}
+			return nil
+		})
+		if !errors.Is(err, expected) {
+			t.Errorf("error %v != %v", err, expected)
+		}
+		assertEvents(t, actual, "a", "b", "c")
+	})
+}